@@ -0,0 +1,235 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/types"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+const dixtestPath = "github.com/pubgo/dix/dixtest"
+
+type generator struct {
+	outPath string
+	outName string
+
+	imports map[string]string
+	names   map[string]bool
+	fakes   map[string]bool
+
+	body  bytes.Buffer
+	inits bytes.Buffer
+}
+
+func newGenerator(outPath, outName string) *generator {
+	return &generator{
+		outPath: outPath,
+		outName: outName,
+		imports: map[string]string{dixtestPath: "dixtest"},
+		names:   map[string]bool{"dixtest": true},
+		fakes:   make(map[string]bool),
+	}
+}
+
+// findInterface returns the named interface type of the loaded packages
+func findInterface(pkgs []*packages.Package, name string) (*types.Named, error) {
+	for _, pkg := range pkgs {
+		obj, ok := pkg.Types.Scope().Lookup(name).(*types.TypeName)
+		if !ok {
+			continue
+		}
+
+		named, ok := obj.Type().(*types.Named)
+		if !ok {
+			return nil, fmt.Errorf("%s is an alias, use the aliased type", name)
+		}
+
+		iface, ok := named.Underlying().(*types.Interface)
+		if !ok || !iface.IsMethodSet() {
+			return nil, fmt.Errorf("%s is not an interface with methods only", name)
+		}
+
+		if named.TypeParams().Len() > 0 {
+			return nil, fmt.Errorf("%s is generic, generic interfaces are not supported", name)
+		}
+		return named, nil
+	}
+	return nil, fmt.Errorf("interface %s not found", name)
+}
+
+func (g *generator) addFake(named *types.Named) error {
+	if err := g.checkType(named); err != nil {
+		return err
+	}
+
+	name := g.fakeName(named)
+	iface := named.Underlying().(*types.Interface)
+	fmt.Fprintf(&g.body, "// %s is the recording fake of %s\n", name, g.typeExpr(named))
+	fmt.Fprintf(&g.body, "type %s struct{ recorder *dixtest.Recorder }\n\n", name)
+	fmt.Fprintf(&g.body, "func (f *%s) DixFakeRecorder() *dixtest.Recorder { return f.recorder }\n\n", name)
+
+	for i := 0; i < iface.NumMethods(); i++ {
+		m := iface.Method(i)
+		if !m.Exported() && m.Pkg().Path() != g.outPath {
+			return fmt.Errorf("method %s is not exported", m.Name())
+		}
+
+		sig := m.Type().(*types.Signature)
+		if err := g.checkType(sig); err != nil {
+			return fmt.Errorf("method %s: %w", m.Name(), err)
+		}
+
+		var params, args, results []string
+		for j := 0; j < sig.Params().Len(); j++ {
+			typ := g.typeExpr(sig.Params().At(j).Type())
+			if sig.Variadic() && j == sig.Params().Len()-1 {
+				typ = "..." + strings.TrimPrefix(typ, "[]")
+			}
+
+			params = append(params, fmt.Sprintf("p%d %s", j, typ))
+			args = append(args, fmt.Sprintf("p%d", j))
+		}
+
+		for j := 0; j < sig.Results().Len(); j++ {
+			results = append(results, fmt.Sprintf("r%d %s", j, g.typeExpr(sig.Results().At(j).Type())))
+		}
+
+		record := fmt.Sprintf("%q", m.Name())
+		if len(args) > 0 {
+			record += ", " + strings.Join(args, ", ")
+		}
+
+		fmt.Fprintf(&g.body, "func (f *%s) %s(%s)", name, m.Name(), strings.Join(params, ", "))
+		if len(results) > 0 {
+			fmt.Fprintf(&g.body, " (%s)", strings.Join(results, ", "))
+		}
+		fmt.Fprintf(&g.body, " {\nf.recorder.Record(%s)\n", record)
+		if len(results) > 0 {
+			g.body.WriteString("return\n")
+		}
+		g.body.WriteString("}\n\n")
+	}
+
+	fmt.Fprintf(&g.inits, "dixtest.RegisterFake(func(r *dixtest.Recorder) %s { return &%s{recorder: r} })\n", g.typeExpr(named), name)
+	return nil
+}
+
+// fakeName returns the type name of the fake, it is prefixed with the package name on conflicts
+func (g *generator) fakeName(named *types.Named) string {
+	obj := named.Obj()
+	name := "fake" + upperFirst(obj.Name())
+	if g.fakes[name] {
+		name = "fake" + upperFirst(obj.Pkg().Name()) + upperFirst(obj.Name())
+	}
+	for i := 2; g.fakes[name]; i++ {
+		name = fmt.Sprintf("fake%s%d", upperFirst(obj.Name()), i)
+	}
+
+	g.fakes[name] = true
+	return name
+}
+
+func (g *generator) file() []byte {
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by dixfake. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", g.outName)
+
+	var paths []string
+	for path := range g.imports {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	buf.WriteString("import (\n")
+	for _, path := range paths {
+		name := g.imports[path]
+		if name == lastSegment(path) {
+			fmt.Fprintf(&buf, "%q\n", path)
+		} else {
+			fmt.Fprintf(&buf, "%s %q\n", name, path)
+		}
+	}
+	buf.WriteString(")\n\n")
+
+	buf.WriteString("func init() {\n")
+	buf.Write(g.inits.Bytes())
+	buf.WriteString("}\n\n")
+	buf.Write(g.body.Bytes())
+	return buf.Bytes()
+}
+
+func (g *generator) qualifier(pkg *types.Package) string {
+	if pkg.Path() == g.outPath {
+		return ""
+	}
+
+	if name, ok := g.imports[pkg.Path()]; ok {
+		return name
+	}
+
+	name := pkg.Name()
+	for i := 2; g.names[name]; i++ {
+		name = fmt.Sprintf("%s%d", pkg.Name(), i)
+	}
+
+	g.names[name] = true
+	g.imports[pkg.Path()] = name
+	return name
+}
+
+func (g *generator) typeExpr(t types.Type) string {
+	return types.TypeString(t, g.qualifier)
+}
+
+// checkType makes sure the type can be referenced from the output package
+func (g *generator) checkType(t types.Type) error {
+	switch u := t.(type) {
+	case *types.Named:
+		obj := u.Obj()
+		if obj.Pkg() != nil && obj.Pkg().Path() != g.outPath && !obj.Exported() {
+			return fmt.Errorf("type is not exported, type=%s", t)
+		}
+
+		for i := 0; i < u.TypeArgs().Len(); i++ {
+			if err := g.checkType(u.TypeArgs().At(i)); err != nil {
+				return err
+			}
+		}
+	case *types.Pointer:
+		return g.checkType(u.Elem())
+	case *types.Slice:
+		return g.checkType(u.Elem())
+	case *types.Array:
+		return g.checkType(u.Elem())
+	case *types.Chan:
+		return g.checkType(u.Elem())
+	case *types.Map:
+		if err := g.checkType(u.Key()); err != nil {
+			return err
+		}
+		return g.checkType(u.Elem())
+	case *types.Signature:
+		for _, tuple := range []*types.Tuple{u.Params(), u.Results()} {
+			for i := 0; i < tuple.Len(); i++ {
+				if err := g.checkType(tuple.At(i).Type()); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+func lastSegment(path string) string {
+	return path[strings.LastIndex(path, "/")+1:]
+}
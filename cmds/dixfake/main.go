@@ -0,0 +1,95 @@
+// Command dixfake generates recording fakes of interfaces for the test mode of dixtest.
+//
+//	//go:generate dixfake -type Mailer,Store -o dixfake_test.go .
+//
+// Every method of a fake records the call on its dixtest.Recorder and returns zero values.
+// The generated init registers the fakes with dixtest.RegisterFake, so a container of
+// dixtest.New(t, dixtest.WithFakes(), ...) resolves those interfaces without providers to new fakes,
+// and dixtest.FakeOf returns the recorded calls.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("dixfake: ")
+
+	typeNames := flag.String("type", "", "comma separated interface names, required")
+	output := flag.String("o", "dixfake_test.go", "output file, generated into the package of its directory")
+	flag.Parse()
+
+	if *typeNames == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	patterns := flag.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+
+	if err := run(*output, strings.Split(*typeNames, ","), patterns); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(output string, typeNames, patterns []string) error {
+	outDir, err := filepath.Abs(filepath.Dir(output))
+	if err != nil {
+		return err
+	}
+
+	mode := packages.NeedName | packages.NeedTypes | packages.NeedImports
+	pkgs, err := packages.Load(&packages.Config{Mode: mode}, patterns...)
+	if err != nil {
+		return fmt.Errorf("failed to load packages: %w", err)
+	}
+
+	if packages.PrintErrors(pkgs) > 0 {
+		return fmt.Errorf("packages contain errors")
+	}
+
+	outPkgs, err := packages.Load(&packages.Config{Mode: packages.NeedName, Dir: outDir}, ".")
+	if err != nil || len(outPkgs) == 0 {
+		return fmt.Errorf("failed to load output package, dir=%s err=%v", outDir, err)
+	}
+
+	src, err := generate(pkgs, outPkgs[0].PkgPath, outPkgs[0].Name, typeNames)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(output, src, 0o644)
+}
+
+// generate returns the formatted fakes of the interfaces for the output package
+func generate(pkgs []*packages.Package, outPath, outName string, typeNames []string) ([]byte, error) {
+	g := newGenerator(outPath, outName)
+	for _, name := range typeNames {
+		iface, err := findInterface(pkgs, strings.TrimSpace(name))
+		if err != nil {
+			return nil, err
+		}
+
+		if err := g.addFake(iface); err != nil {
+			return nil, fmt.Errorf("%s: %w", iface.Obj().Name(), err)
+		}
+	}
+
+	src := g.file()
+	formatted, err := format.Source(src)
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated code: %w\n%s", err, src)
+	}
+	return formatted, nil
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// TestExampleFakes type checks example/fake with the committed fakes and regenerates them
+func TestExampleFakes(t *testing.T) {
+	dir := filepath.Join("..", "..", "example", "fake")
+
+	mode := packages.NeedName | packages.NeedTypes | packages.NeedImports
+	pkgs, err := packages.Load(&packages.Config{Mode: mode, Dir: dir}, ".")
+	if err != nil {
+		t.Fatalf("failed to load the example: %v", err)
+	}
+
+	if packages.PrintErrors(pkgs) > 0 {
+		t.Fatal("the example does not compile with the generated fakes")
+	}
+
+	src, err := generate(pkgs, pkgs[0].PkgPath, pkgs[0].Name, []string{"Mailer", "Audit"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want, err := os.ReadFile(filepath.Join(dir, "dixfake.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(src, want) {
+		t.Fatalf("example/fake/dixfake.go is out of date, run go generate in example/fake\n%s", src)
+	}
+}
@@ -0,0 +1,688 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/types"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+type inputKind int
+
+const (
+	inputOne inputKind = iota
+	inputList
+	inputMap
+	inputMapList
+	inputStruct
+)
+
+// input mirrors the dix input kinds, typ is the element type, or the struct type of a parameter object
+type input struct {
+	kind   inputKind
+	typ    types.Type
+	fields []*fieldInput
+}
+
+type fieldInput struct {
+	name string
+	in   *input
+}
+
+type outputKind int
+
+const (
+	outputOne outputKind = iota
+	outputList
+	outputMap
+	outputMapList
+	outputStruct
+)
+
+type output struct {
+	kind   outputKind
+	typ    types.Type
+	fields []*fieldOutput
+}
+
+type fieldOutput struct {
+	name string
+	out  *output
+}
+
+type node struct {
+	p        *provider
+	inputs   []*input
+	out      *output
+	hasError bool
+	state    int
+}
+
+type generator struct {
+	outPath string
+	outName string
+
+	imports map[string]string
+	names   map[string]bool
+
+	fields     map[string]string
+	fieldOrder []string
+	fieldTypes map[string]types.Type
+
+	body bytes.Buffer
+	tmp  int
+}
+
+func newGenerator(outPath, outName string) *generator {
+	return &generator{
+		outPath:    outPath,
+		outName:    outName,
+		imports:    map[string]string{"fmt": "fmt", "strings": "strings"},
+		names:      map[string]bool{"fmt": true, "strings": true},
+		fields:     make(map[string]string),
+		fieldTypes: make(map[string]types.Type),
+	}
+}
+
+func (g *generator) generate(providers []*provider) ([]byte, error) {
+	var nodes []*node
+	byType := make(map[string][]*node)
+	for _, p := range providers {
+		n, err := g.parseNode(p)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", p, err)
+		}
+
+		nodes = append(nodes, n)
+		for _, t := range outputTypes(n.out) {
+			byType[typeKey(t)] = append(byType[typeKey(t)], n)
+		}
+	}
+
+	var (
+		stack []*node
+		visit func(n *node) error
+	)
+	visit = func(n *node) error {
+		switch n.state {
+		case 1:
+			var path []string
+			for _, s := range stack[slices.Index(stack, n):] {
+				path = append(path, s.p.fn.FullName())
+			}
+			return fmt.Errorf("circular dependency, path=%s -> %s", strings.Join(path, " -> "), n.p.fn.FullName())
+		case 2:
+			return nil
+		}
+
+		n.state = 1
+		stack = append(stack, n)
+		for _, in := range n.inputs {
+			for _, t := range inputTypes(in) {
+				for _, dep := range byType[typeKey(t)] {
+					if err := visit(dep); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		n.state = 2
+		return g.emitNode(n)
+	}
+
+	for _, n := range nodes {
+		if err := visit(n); err != nil {
+			return nil, err
+		}
+	}
+
+	return g.file()
+}
+
+func (g *generator) parseNode(p *provider) (*node, error) {
+	if err := g.checkFunc(p.fn); err != nil {
+		return nil, err
+	}
+
+	sig := p.sig
+	switch {
+	case sig.Variadic():
+		return nil, fmt.Errorf("the func of provider variable parameters are not allowed")
+	case sig.Results().Len() == 0:
+		return nil, fmt.Errorf("the func of provider output num should not be zero")
+	case sig.Results().Len() > 2:
+		return nil, fmt.Errorf("the func of provider output num should <= two")
+	}
+
+	n := &node{p: p}
+	if sig.Results().Len() == 2 {
+		if !isError(sig.Results().At(1).Type()) {
+			return nil, fmt.Errorf("second return value must be error type")
+		}
+		n.hasError = true
+	}
+
+	for i := 0; i < sig.Params().Len(); i++ {
+		in, err := g.parseInput(sig.Params().At(i).Type())
+		if err != nil {
+			return nil, err
+		}
+		n.inputs = append(n.inputs, in)
+	}
+
+	out, err := g.parseOutput(sig.Results().At(0).Type())
+	if err != nil {
+		return nil, err
+	}
+	n.out = out
+	return n, nil
+}
+
+func (g *generator) parseInput(t types.Type) (*input, error) {
+	switch u := t.Underlying().(type) {
+	case *types.Pointer, *types.Interface, *types.Signature:
+		return g.newInput(inputOne, t)
+	case *types.Slice:
+		if !isProvidable(u.Elem()) {
+			return nil, fmt.Errorf("input list element value type kind not support, type=%s", t)
+		}
+		return g.newInput(inputList, u.Elem())
+	case *types.Map:
+		if !isString(u.Key()) {
+			return nil, fmt.Errorf("input map key should be string, type=%s", t)
+		}
+
+		if elem, ok := u.Elem().Underlying().(*types.Slice); ok && isProvidable(elem.Elem()) {
+			return g.newInput(inputMapList, elem.Elem())
+		}
+
+		if !isProvidable(u.Elem()) {
+			return nil, fmt.Errorf("input map value type kind not support, type=%s", t)
+		}
+		return g.newInput(inputMap, u.Elem())
+	case *types.Struct:
+		return g.parseStructInput(t, u)
+	default:
+		return nil, fmt.Errorf("incorrect input type, type=%s", t)
+	}
+}
+
+func (g *generator) newInput(kind inputKind, t types.Type) (*input, error) {
+	if err := g.checkType(t); err != nil {
+		return nil, err
+	}
+
+	g.field(t)
+	return &input{kind: kind, typ: t}, nil
+}
+
+func (g *generator) parseStructInput(t types.Type, st *types.Struct) (*input, error) {
+	if err := g.checkType(t); err != nil {
+		return nil, err
+	}
+
+	in := &input{kind: inputStruct, typ: t}
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+		if !field.Exported() {
+			continue
+		}
+
+		tag := reflect.StructTag(st.Tag(i))
+		for _, name := range []string{"env", "flag", "default"} {
+			if _, ok := tag.Lookup(name); ok {
+				return nil, fmt.Errorf("value tags are not supported by dixgen, field=%s tag=%s", field.Name(), name)
+			}
+		}
+
+		fieldIn, err := g.parseInput(field.Type())
+		if err != nil {
+			return nil, fmt.Errorf("field=%s: %w", field.Name(), err)
+		}
+		in.fields = append(in.fields, &fieldInput{name: field.Name(), in: fieldIn})
+	}
+
+	return in, nil
+}
+
+func (g *generator) parseOutput(t types.Type) (*output, error) {
+	switch u := t.Underlying().(type) {
+	case *types.Pointer, *types.Interface, *types.Signature:
+		return g.newOutput(outputOne, t)
+	case *types.Slice:
+		if !isProvidable(u.Elem()) {
+			return nil, fmt.Errorf("output list element value type kind not support, type=%s", t)
+		}
+		return g.newOutput(outputList, u.Elem())
+	case *types.Map:
+		if !isString(u.Key()) {
+			return nil, fmt.Errorf("output map key should be string, type=%s", t)
+		}
+
+		if elem, ok := u.Elem().Underlying().(*types.Slice); ok && isProvidable(elem.Elem()) {
+			return g.newOutput(outputMapList, elem.Elem())
+		}
+
+		if !isProvidable(u.Elem()) {
+			return nil, fmt.Errorf("output map value type kind not support, type=%s", t)
+		}
+		return g.newOutput(outputMap, u.Elem())
+	case *types.Struct:
+		out := &output{kind: outputStruct, typ: t}
+		for i := 0; i < u.NumFields(); i++ {
+			field := u.Field(i)
+			if !field.Exported() || !isSupported(field.Type()) {
+				continue
+			}
+
+			fieldOut, err := g.parseOutput(field.Type())
+			if err != nil {
+				return nil, fmt.Errorf("field=%s: %w", field.Name(), err)
+			}
+			out.fields = append(out.fields, &fieldOutput{name: field.Name(), out: fieldOut})
+		}
+		return out, nil
+	default:
+		return nil, fmt.Errorf("incorrect output type, type=%s", t)
+	}
+}
+
+func (g *generator) newOutput(kind outputKind, t types.Type) (*output, error) {
+	if err := g.checkType(t); err != nil {
+		return nil, err
+	}
+
+	g.field(t)
+	return &output{kind: kind, typ: t}, nil
+}
+
+func outputTypes(out *output) []types.Type {
+	if out.kind != outputStruct {
+		return []types.Type{out.typ}
+	}
+
+	var list []types.Type
+	for _, f := range out.fields {
+		list = append(list, outputTypes(f.out)...)
+	}
+	return list
+}
+
+func inputTypes(in *input) []types.Type {
+	if in.kind != inputStruct {
+		return []types.Type{in.typ}
+	}
+
+	var list []types.Type
+	for _, f := range in.fields {
+		list = append(list, inputTypes(f.in)...)
+	}
+	return list
+}
+
+func (g *generator) emitNode(n *node) error {
+	// the position would make the output depend on the checkout directory
+	g.printf("\n// %s\n{\n", n.p.fn.FullName())
+
+	var args []string
+	for _, in := range n.inputs {
+		args = append(args, g.emitInput(in))
+	}
+
+	call := fmt.Sprintf("%s(%s)", g.funcExpr(n.p.fn), strings.Join(args, ", "))
+	if n.hasError {
+		g.printf("v, err := %s\n", call)
+		g.printf("if err != nil {\nreturn nil, fmt.Errorf(\"failed to do provider, provider=%%s: %%w\", %q, err)\n}\n", n.p.fn.FullName())
+	} else {
+		g.printf("v := %s\n", call)
+	}
+
+	g.emitOutput("v", n.out)
+	if len(outputTypes(n.out)) == 0 {
+		g.printf("_ = v\n")
+	}
+	g.printf("}\n")
+	return nil
+}
+
+// emitInput emits the statements resolving the input and returns the variable holding it
+func (g *generator) emitInput(in *input) string {
+	name := g.tmpName("p")
+	switch in.kind {
+	case inputOne:
+		g.printf("%s, ok := o.%s.Get()\n", name, g.field(in.typ))
+		g.printf("if !ok {\nreturn nil, dixNotFound(%q)\n}\n", types.TypeString(in.typ, nil))
+	case inputList:
+		g.printf("%s := o.%s.List()\n", name, g.field(in.typ))
+	case inputMap:
+		g.printf("%s := o.%s.Map()\n", name, g.field(in.typ))
+	case inputMapList:
+		g.printf("%s := o.%s.MapList()\n", name, g.field(in.typ))
+	case inputStruct:
+		var fields []string
+		for _, f := range in.fields {
+			fields = append(fields, fmt.Sprintf("%s: %s", f.name, g.emitInput(f.in)))
+		}
+		g.printf("%s := %s{%s}\n", name, g.typeExpr(in.typ), strings.Join(fields, ", "))
+	}
+	return name
+}
+
+// emitOutput emits the statements storing the provider result, nil values are dropped like dix handleOutput
+func (g *generator) emitOutput(expr string, out *output) {
+	switch out.kind {
+	case outputOne:
+		g.printf("if %s != nil {\no.%s.add(dixDefaultKey, %s)\n}\n", expr, g.field(out.typ), expr)
+	case outputList:
+		e := g.tmpName("e")
+		g.printf("for _, %s := range %s {\nif %s != nil {\no.%s.add(dixDefaultKey, %s)\n}\n}\n", e, expr, e, g.field(out.typ), e)
+	case outputMap:
+		k, e := g.tmpName("k"), g.tmpName("e")
+		g.printf("for %s, %s := range %s {\nif %s != nil {\no.%s.add(dixNamespace(%s), %s)\n}\n}\n", k, e, expr, e, g.field(out.typ), k, e)
+	case outputMapList:
+		k, l, e := g.tmpName("k"), g.tmpName("l"), g.tmpName("e")
+		g.printf("for %s, %s := range %s {\nfor _, %s := range %s {\nif %s != nil {\no.%s.add(dixNamespace(%s), %s)\n}\n}\n}\n",
+			k, l, expr, e, l, e, g.field(out.typ), k, e)
+	case outputStruct:
+		for _, f := range out.fields {
+			g.emitOutput(expr+"."+f.name, f.out)
+		}
+	}
+}
+
+func (g *generator) file() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by dixgen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", g.outName)
+
+	var paths []string
+	for path := range g.imports {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	buf.WriteString("import (\n")
+	for _, path := range paths {
+		name := g.imports[path]
+		if name == lastSegment(path) {
+			fmt.Fprintf(&buf, "%q\n", path)
+		} else {
+			fmt.Fprintf(&buf, "%s %q\n", name, path)
+		}
+	}
+	buf.WriteString(")\n\n")
+
+	buf.WriteString("// DixObjects holds the provided values of each type\ntype DixObjects struct {\n")
+	for _, key := range g.fieldOrder {
+		fmt.Fprintf(&buf, "%s DixValues[%s]\n", g.fields[key], g.typeExpr(g.fieldTypes[key]))
+	}
+	buf.WriteString("}\n\n")
+
+	buf.WriteString("// DixBuild calls every provider in dependency order, it is the static version of dix.Dix.Build\n")
+	buf.WriteString("func DixBuild() (*DixObjects, error) {\no := new(DixObjects)\n")
+	buf.Write(g.body.Bytes())
+	buf.WriteString("return o, nil\n}\n")
+	buf.WriteString(runtimeSource)
+	return buf.Bytes(), nil
+}
+
+// field returns the DixObjects field name of the type
+func (g *generator) field(t types.Type) string {
+	key := typeKey(t)
+	if name, ok := g.fields[key]; ok {
+		return name
+	}
+
+	base := typeIdent(t)
+	name := base
+	used := make(map[string]bool)
+	for _, n := range g.fields {
+		used[n] = true
+	}
+	for i := 2; used[name]; i++ {
+		name = fmt.Sprintf("%s%d", base, i)
+	}
+
+	g.fields[key] = name
+	g.fieldTypes[key] = t
+	g.fieldOrder = append(g.fieldOrder, key)
+	return name
+}
+
+func (g *generator) qualifier(pkg *types.Package) string {
+	if pkg.Path() == g.outPath {
+		return ""
+	}
+
+	if name, ok := g.imports[pkg.Path()]; ok {
+		return name
+	}
+
+	name := pkg.Name()
+	for i := 2; g.names[name]; i++ {
+		name = fmt.Sprintf("%s%d", pkg.Name(), i)
+	}
+
+	g.names[name] = true
+	g.imports[pkg.Path()] = name
+	return name
+}
+
+func (g *generator) typeExpr(t types.Type) string {
+	return types.TypeString(t, g.qualifier)
+}
+
+func (g *generator) funcExpr(fn *types.Func) string {
+	if q := g.qualifier(fn.Pkg()); q != "" {
+		return q + "." + fn.Name()
+	}
+	return fn.Name()
+}
+
+func (g *generator) checkFunc(fn *types.Func) error {
+	if fn.Pkg().Path() == g.outPath {
+		return nil
+	}
+
+	if !fn.Exported() {
+		return fmt.Errorf("provider func is not exported")
+	}
+
+	if fn.Pkg().Name() == "main" {
+		return fmt.Errorf("provider func is in another main package")
+	}
+	return nil
+}
+
+// checkType makes sure the type can be referenced from the output package
+func (g *generator) checkType(t types.Type) error {
+	switch u := t.(type) {
+	case *types.Named:
+		obj := u.Obj()
+		if obj.Pkg() != nil && obj.Pkg().Path() != g.outPath && !obj.Exported() {
+			return fmt.Errorf("type is not exported, type=%s", t)
+		}
+
+		for i := 0; i < u.TypeArgs().Len(); i++ {
+			if err := g.checkType(u.TypeArgs().At(i)); err != nil {
+				return err
+			}
+		}
+	case *types.Pointer:
+		return g.checkType(u.Elem())
+	case *types.Slice:
+		return g.checkType(u.Elem())
+	case *types.Map:
+		if err := g.checkType(u.Key()); err != nil {
+			return err
+		}
+		return g.checkType(u.Elem())
+	case *types.Struct:
+		for i := 0; i < u.NumFields(); i++ {
+			field := u.Field(i)
+			if !field.Exported() && field.Pkg() != nil && field.Pkg().Path() != g.outPath {
+				return fmt.Errorf("struct type has unexported fields, type=%s", t)
+			}
+
+			if err := g.checkType(field.Type()); err != nil {
+				return err
+			}
+		}
+	case *types.Signature:
+		for _, tuple := range []*types.Tuple{u.Params(), u.Results()} {
+			for i := 0; i < tuple.Len(); i++ {
+				if err := g.checkType(tuple.At(i).Type()); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (g *generator) tmpName(prefix string) string {
+	g.tmp++
+	return fmt.Sprintf("%s%d", prefix, g.tmp)
+}
+
+func (g *generator) printf(format string, args ...any) {
+	fmt.Fprintf(&g.body, format, args...)
+}
+
+func typeKey(t types.Type) string {
+	return types.TypeString(t, nil)
+}
+
+// typeIdent returns an exported identifier for the type, e.g. *db.Client -> PtrDbClient
+func typeIdent(t types.Type) string {
+	switch u := t.(type) {
+	case *types.Pointer:
+		return "Ptr" + typeIdent(u.Elem())
+	case *types.Named:
+		var prefix string
+		if u.Obj().Pkg() != nil {
+			prefix = upperFirst(u.Obj().Pkg().Name())
+		}
+		return prefix + upperFirst(u.Obj().Name())
+	case *types.Signature:
+		return "Func"
+	case *types.Interface:
+		return "Interface"
+	default:
+		var b strings.Builder
+		for _, r := range t.String() {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				b.WriteRune(r)
+			}
+		}
+		return "T" + upperFirst(b.String())
+	}
+}
+
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+func lastSegment(path string) string {
+	return path[strings.LastIndex(path, "/")+1:]
+}
+
+func isProvidable(t types.Type) bool {
+	switch t.Underlying().(type) {
+	case *types.Pointer, *types.Interface, *types.Signature:
+		return true
+	default:
+		return false
+	}
+}
+
+func isSupported(t types.Type) bool {
+	switch u := t.Underlying().(type) {
+	case *types.Pointer, *types.Interface, *types.Signature, *types.Struct:
+		return true
+	case *types.Map:
+		return isProvidable(u.Elem())
+	case *types.Slice:
+		return isProvidable(u.Elem())
+	default:
+		return false
+	}
+}
+
+func isString(t types.Type) bool {
+	basic, ok := t.Underlying().(*types.Basic)
+	return ok && basic.Kind() == types.String
+}
+
+var errorType = types.Universe.Lookup("error").Type().Underlying().(*types.Interface)
+
+func isError(t types.Type) bool {
+	return types.Implements(t, errorType)
+}
+
+const runtimeSource = `
+const dixDefaultKey = "default"
+
+// DixValues holds the values of one type by namespace, in provider evaluation order
+type DixValues[T any] map[string][]T
+
+func (v *DixValues[T]) add(ns string, val T) {
+	if *v == nil {
+		*v = make(DixValues[T])
+	}
+	(*v)[ns] = append((*v)[ns], val)
+}
+
+// Get returns the last value of the default namespace
+func (v DixValues[T]) Get() (T, bool) {
+	values := v[dixDefaultKey]
+	if len(values) == 0 {
+		var zero T
+		return zero, false
+	}
+	return values[len(values)-1], true
+}
+
+// List returns the values of the default namespace
+func (v DixValues[T]) List() []T {
+	return append(make([]T, 0, len(v[dixDefaultKey])), v[dixDefaultKey]...)
+}
+
+// Map returns the last value of every namespace
+func (v DixValues[T]) Map() map[string]T {
+	data := make(map[string]T, len(v))
+	for ns, values := range v {
+		data[ns] = values[len(values)-1]
+	}
+	return data
+}
+
+// MapList returns the values of every namespace
+func (v DixValues[T]) MapList() map[string][]T {
+	data := make(map[string][]T, len(v))
+	for ns, values := range v {
+		data[ns] = append(make([]T, 0, len(values)), values...)
+	}
+	return data
+}
+
+func dixNamespace(key string) string {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return dixDefaultKey
+	}
+	return key
+}
+
+func dixNotFound(typ string) error {
+	return fmt.Errorf("provider value not found, type=%s", typ)
+}
+`
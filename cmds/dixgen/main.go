@@ -0,0 +1,88 @@
+// Command dixgen generates static wiring code from dix providers.
+//
+// It finds the provider funcs registered with dix.Provide, (*dix.Dix).Provide and dixglobal.Provide,
+// and generates DixBuild, which calls them in dependency order without reflection.
+//
+//	dixgen -o dix_gen.go ./...
+//
+// The generated code follows dix semantics: the last value of the default namespace wins,
+// map keys are namespaces, lists collect the values of the default namespace and nil values are dropped.
+// Only package level provider funcs can be referenced, a provider which can not be wired statically,
+// e.g. a func literal, a conditional provider or a field with a value tag, fails the generation.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("dixgen: ")
+
+	output := flag.String("o", "dix_gen.go", "output file, generated into the package of its directory")
+	flag.Parse()
+
+	patterns := flag.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+
+	if err := run(*output, patterns); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(output string, patterns []string) error {
+	outDir, err := filepath.Abs(filepath.Dir(output))
+	if err != nil {
+		return err
+	}
+
+	mode := packages.NeedName | packages.NeedFiles | packages.NeedSyntax | packages.NeedTypes | packages.NeedTypesInfo | packages.NeedImports
+	pkgs, err := packages.Load(&packages.Config{Mode: mode}, patterns...)
+	if err != nil {
+		return fmt.Errorf("failed to load packages: %w", err)
+	}
+
+	if packages.PrintErrors(pkgs) > 0 {
+		return fmt.Errorf("packages contain errors")
+	}
+
+	outPkgs, err := packages.Load(&packages.Config{Mode: packages.NeedName, Dir: outDir}, ".")
+	if err != nil || len(outPkgs) == 0 {
+		return fmt.Errorf("failed to load output package, dir=%s err=%v", outDir, err)
+	}
+
+	src, err := generate(pkgs, outPkgs[0].PkgPath, outPkgs[0].Name)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(output, src, 0o644)
+}
+
+// generate returns the formatted wiring of the providers in pkgs for the output package
+func generate(pkgs []*packages.Package, outPath, outName string) ([]byte, error) {
+	providers, err := findProviders(pkgs)
+	if err != nil {
+		return nil, err
+	}
+
+	src, err := newGenerator(outPath, outName).generate(providers)
+	if err != nil {
+		return nil, err
+	}
+
+	formatted, err := format.Source(src)
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated code: %w\n%s", err, src)
+	}
+	return formatted, nil
+}
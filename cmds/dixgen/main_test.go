@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func loadFixture(t *testing.T, name string) []*packages.Package {
+	t.Helper()
+
+	mode := packages.NeedName | packages.NeedFiles | packages.NeedSyntax | packages.NeedTypes | packages.NeedTypesInfo | packages.NeedImports
+	pkgs, err := packages.Load(&packages.Config{Mode: mode, Dir: filepath.Join("testdata", name)}, ".")
+	if err != nil {
+		t.Fatalf("failed to load the fixture: %v", err)
+	}
+
+	if packages.PrintErrors(pkgs) > 0 {
+		t.Fatalf("the fixture %s does not compile", name)
+	}
+	return pkgs
+}
+
+// TestGolden type checks the fixture with the committed wiring and regenerates it,
+// it covers list, map, struct and error outputs and NewLastDB registered after NewDB wins
+func TestGolden(t *testing.T) {
+	pkgs := loadFixture(t, "fixture")
+
+	src, err := generate(pkgs, pkgs[0].PkgPath, pkgs[0].Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want, err := os.ReadFile(filepath.Join("testdata", "fixture", "dix_gen.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(src, want) {
+		t.Fatalf("testdata/fixture/dix_gen.go is out of date, run go generate in testdata/fixture\n%s", src)
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	for _, tt := range []struct {
+		fixture string
+		err     string
+	}{
+		{"cycle", "circular dependency, path=github.com/pubgo/dix/cmds/dixgen/testdata/cycle.NewA -> github.com/pubgo/dix/cmds/dixgen/testdata/cycle.NewB -> github.com/pubgo/dix/cmds/dixgen/testdata/cycle.NewA"},
+		{"conditional", "conditional providers are not supported"},
+		{"literal", "provider is not a package level func"},
+		{"valuetag", "value tags are not supported by dixgen, field=Addr tag=env"},
+	} {
+		t.Run(tt.fixture, func(t *testing.T) {
+			pkgs := loadFixture(t, tt.fixture)
+
+			_, err := generate(pkgs, pkgs[0].PkgPath, pkgs[0].Name)
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Fatalf("err=%v, want %q", err, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"slices"
+
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// provideFuncs maps the provide funcs to the index of the first provider argument,
+// private module providers are generated like public ones, dix.Provide may take the container first
+var provideFuncs = map[string]int{
+	"github.com/pubgo/dix.Provide":                    0,
+	"github.com/pubgo/dix.Private":                    0,
+	"github.com/pubgo/dix/dixglobal.Provide":          0,
+	"(*github.com/pubgo/dix/dixinternal.Dix).Provide": 0,
+}
+
+type provider struct {
+	fn  *types.Func
+	sig *types.Signature
+	pos token.Position
+}
+
+func (p *provider) String() string {
+	return fmt.Sprintf("%s (%s)", p.fn.FullName(), p.pos)
+}
+
+// findProviders returns the provider funcs in source order, which is the registration order within a package,
+// every provider which can not be wired statically is an error, dropping it would change the wiring
+func findProviders(pkgs []*packages.Package) ([]*provider, error) {
+	var (
+		providers []*provider
+		errs      []error
+	)
+	seen := make(map[*types.Func]bool)
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Syntax {
+			ast.Inspect(file, func(node ast.Node) bool {
+				call, ok := node.(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+
+				callee, ok := typeutil.Callee(pkg.TypesInfo, call).(*types.Func)
+				if !ok {
+					return true
+				}
+
+				argIndex, ok := provideFuncs[callee.FullName()]
+				if !ok || argIndex >= len(call.Args) {
+					return true
+				}
+
+				if call.Ellipsis.IsValid() {
+					errs = append(errs, fmt.Errorf("providers are passed as a slice, pos=%s", pkg.Fset.Position(call.Pos())))
+					return true
+				}
+
+				// the generated code has no runtime state to evaluate dix.If and dix.Profile against
+				if slices.ContainsFunc(call.Args[argIndex:], func(arg ast.Expr) bool { return isCondition(pkg.TypesInfo.TypeOf(arg)) }) {
+					errs = append(errs, fmt.Errorf("conditional providers are not supported, pos=%s", pkg.Fset.Position(call.Pos())))
+					return true
+				}
+
+				// the provide funcs take every argument from argIndex as a provider
+				args := call.Args[argIndex:]
+				if isContainer(pkg.TypesInfo.TypeOf(args[0])) {
+					args = args[1:]
+				}
+
+				for _, arg := range args {
+					arg = ast.Unparen(arg)
+					pos := pkg.Fset.Position(arg.Pos())
+					fn := providerFunc(pkg.TypesInfo, arg)
+					if fn == nil {
+						errs = append(errs, fmt.Errorf("provider is not a package level func, pos=%s", pos))
+						continue
+					}
+
+					if seen[fn] {
+						continue
+					}
+					seen[fn] = true
+
+					providers = append(providers, &provider{fn: fn, sig: fn.Type().(*types.Signature), pos: pos})
+				}
+				return true
+			})
+		}
+	}
+	return providers, errors.Join(errs...)
+}
+
+func isCondition(t types.Type) bool {
+	named, ok := types.Unalias(t).(*types.Named)
+	return ok && named.Obj().Pkg() != nil &&
+		named.Obj().Pkg().Path() == "github.com/pubgo/dix/dixinternal" && named.Obj().Name() == "Condition"
+}
+
+func isContainer(t types.Type) bool {
+	ptr, ok := types.Unalias(t).(*types.Pointer)
+	if !ok {
+		return false
+	}
+
+	named, ok := types.Unalias(ptr.Elem()).(*types.Named)
+	return ok && named.Obj().Pkg() != nil &&
+		named.Obj().Pkg().Path() == "github.com/pubgo/dix/dixinternal" && named.Obj().Name() == "Dix"
+}
+
+func providerFunc(info *types.Info, expr ast.Expr) *types.Func {
+	var ident *ast.Ident
+	switch e := expr.(type) {
+	case *ast.Ident:
+		ident = e
+	case *ast.SelectorExpr:
+		ident = e.Sel
+	default:
+		return nil
+	}
+
+	fn, ok := info.Uses[ident].(*types.Func)
+	if !ok || fn.Type().(*types.Signature).Recv() != nil {
+		return nil
+	}
+	return fn
+}
@@ -0,0 +1,12 @@
+// Package conditional has a provider which depends on the runtime state
+package conditional
+
+import "github.com/pubgo/dix"
+
+type Cache struct{}
+
+func NewCache() *Cache { return new(Cache) }
+
+func Register(di *dix.Dix) {
+	di.Provide(NewCache, dix.Profile("prod"))
+}
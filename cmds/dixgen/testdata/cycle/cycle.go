@@ -0,0 +1,17 @@
+// Package cycle has providers which depend on each other
+package cycle
+
+import "github.com/pubgo/dix"
+
+type A struct{}
+
+type B struct{}
+
+func NewA(*B) *A { return new(A) }
+
+func NewB(*A) *B { return new(B) }
+
+func Register(di *dix.Dix) {
+	dix.Provide(di, NewA)
+	dix.Provide(di, NewB)
+}
@@ -0,0 +1,153 @@
+// Code generated by dixgen. DO NOT EDIT.
+
+package fixture
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DixObjects holds the provided values of each type
+type DixObjects struct {
+	PtrFixtureDB      DixValues[*DB]
+	PtrFixtureCache   DixValues[*Cache]
+	FixtureHandler    DixValues[Handler]
+	PtrFixtureLogger  DixValues[*Logger]
+	PtrFixtureServer  DixValues[*Server]
+	PtrFixtureMetrics DixValues[*Metrics]
+}
+
+// DixBuild calls every provider in dependency order, it is the static version of dix.Dix.Build
+func DixBuild() (*DixObjects, error) {
+	o := new(DixObjects)
+
+	// github.com/pubgo/dix/cmds/dixgen/testdata/fixture.NewDB
+	{
+		v := NewDB()
+		if v != nil {
+			o.PtrFixtureDB.add(dixDefaultKey, v)
+		}
+	}
+
+	// github.com/pubgo/dix/cmds/dixgen/testdata/fixture.NewLastDB
+	{
+		v := NewLastDB()
+		if v != nil {
+			o.PtrFixtureDB.add(dixDefaultKey, v)
+		}
+	}
+
+	// github.com/pubgo/dix/cmds/dixgen/testdata/fixture.NewCaches
+	{
+		v := NewCaches()
+		for k1, e2 := range v {
+			if e2 != nil {
+				o.PtrFixtureCache.add(dixNamespace(k1), e2)
+			}
+		}
+	}
+
+	// github.com/pubgo/dix/cmds/dixgen/testdata/fixture.NewHandlers
+	{
+		v := NewHandlers()
+		for _, e3 := range v {
+			if e3 != nil {
+				o.FixtureHandler.add(dixDefaultKey, e3)
+			}
+		}
+	}
+
+	// github.com/pubgo/dix/cmds/dixgen/testdata/fixture.NewTelemetry
+	{
+		v, err := NewTelemetry()
+		if err != nil {
+			return nil, fmt.Errorf("failed to do provider, provider=%s: %w", "github.com/pubgo/dix/cmds/dixgen/testdata/fixture.NewTelemetry", err)
+		}
+		if v.Log != nil {
+			o.PtrFixtureLogger.add(dixDefaultKey, v.Log)
+		}
+		if v.Metrics != nil {
+			o.PtrFixtureMetrics.add(dixDefaultKey, v.Metrics)
+		}
+	}
+
+	// github.com/pubgo/dix/cmds/dixgen/testdata/fixture.NewServer
+	{
+		p5, ok := o.PtrFixtureDB.Get()
+		if !ok {
+			return nil, dixNotFound("*github.com/pubgo/dix/cmds/dixgen/testdata/fixture.DB")
+		}
+		p6 := o.PtrFixtureCache.Map()
+		p7 := o.FixtureHandler.List()
+		p8, ok := o.PtrFixtureLogger.Get()
+		if !ok {
+			return nil, dixNotFound("*github.com/pubgo/dix/cmds/dixgen/testdata/fixture.Logger")
+		}
+		p4 := Params{DB: p5, Caches: p6, Handlers: p7, Log: p8}
+		v, err := NewServer(p4)
+		if err != nil {
+			return nil, fmt.Errorf("failed to do provider, provider=%s: %w", "github.com/pubgo/dix/cmds/dixgen/testdata/fixture.NewServer", err)
+		}
+		if v != nil {
+			o.PtrFixtureServer.add(dixDefaultKey, v)
+		}
+	}
+	return o, nil
+}
+
+const dixDefaultKey = "default"
+
+// DixValues holds the values of one type by namespace, in provider evaluation order
+type DixValues[T any] map[string][]T
+
+func (v *DixValues[T]) add(ns string, val T) {
+	if *v == nil {
+		*v = make(DixValues[T])
+	}
+	(*v)[ns] = append((*v)[ns], val)
+}
+
+// Get returns the last value of the default namespace
+func (v DixValues[T]) Get() (T, bool) {
+	values := v[dixDefaultKey]
+	if len(values) == 0 {
+		var zero T
+		return zero, false
+	}
+	return values[len(values)-1], true
+}
+
+// List returns the values of the default namespace
+func (v DixValues[T]) List() []T {
+	return append(make([]T, 0, len(v[dixDefaultKey])), v[dixDefaultKey]...)
+}
+
+// Map returns the last value of every namespace
+func (v DixValues[T]) Map() map[string]T {
+	data := make(map[string]T, len(v))
+	for ns, values := range v {
+		data[ns] = values[len(values)-1]
+	}
+	return data
+}
+
+// MapList returns the values of every namespace
+func (v DixValues[T]) MapList() map[string][]T {
+	data := make(map[string][]T, len(v))
+	for ns, values := range v {
+		data[ns] = append(make([]T, 0, len(values)), values...)
+	}
+	return data
+}
+
+func dixNamespace(key string) string {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return dixDefaultKey
+	}
+	return key
+}
+
+func dixNotFound(typ string) error {
+	return fmt.Errorf("provider value not found, type=%s", typ)
+}
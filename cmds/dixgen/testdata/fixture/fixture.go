@@ -0,0 +1,74 @@
+// Package fixture is the golden input of dixgen
+package fixture
+
+import (
+	"errors"
+
+	"github.com/pubgo/dix"
+)
+
+//go:generate go run github.com/pubgo/dix/cmds/dixgen -o dix_gen.go .
+
+type DB struct{ Name string }
+
+type Cache struct{ Name string }
+
+type Handler interface{ Name() string }
+
+type Logger struct{}
+
+type Metrics struct{}
+
+type Server struct {
+	DB       *DB
+	Caches   map[string]*Cache
+	Handlers []Handler
+}
+
+// Telemetry is a struct output, each field is provided on its own
+type Telemetry struct {
+	Log     *Logger
+	Metrics *Metrics
+}
+
+type Params struct {
+	DB       *DB
+	Caches   map[string]*Cache
+	Handlers []Handler
+	Log      *Logger
+}
+
+type handler string
+
+func (h handler) Name() string { return string(h) }
+
+func NewDB() *DB { return &DB{Name: "first"} }
+
+// NewLastDB is registered after NewDB, the last value wins
+func NewLastDB() *DB { return &DB{Name: "last"} }
+
+func NewCaches() map[string]*Cache {
+	return map[string]*Cache{"hot": {Name: "hot"}, "cold": {Name: "cold"}}
+}
+
+func NewHandlers() []Handler { return []Handler{handler("a"), handler("b")} }
+
+func NewTelemetry() (Telemetry, error) {
+	return Telemetry{Log: new(Logger), Metrics: new(Metrics)}, nil
+}
+
+func NewServer(p Params) (*Server, error) {
+	if p.Log == nil {
+		return nil, errors.New("logger is required")
+	}
+	return &Server{DB: p.DB, Caches: p.Caches, Handlers: p.Handlers}, nil
+}
+
+func Register(di *dix.Dix) {
+	dix.Provide(di, NewServer)
+	dix.Provide(di, NewDB)
+	dix.Provide(di, NewLastDB)
+	dix.Provide(di, NewCaches)
+	dix.Provide(di, NewHandlers)
+	dix.Provide(di, NewTelemetry)
+}
@@ -0,0 +1,10 @@
+// Package literal has a provider which is a func literal
+package literal
+
+import "github.com/pubgo/dix"
+
+type Cache struct{}
+
+func Register(di *dix.Dix) {
+	dix.Provide(di, func() *Cache { return new(Cache) })
+}
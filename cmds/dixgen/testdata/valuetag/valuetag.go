@@ -0,0 +1,16 @@
+// Package valuetag has a provider with a value tagged field
+package valuetag
+
+import "github.com/pubgo/dix"
+
+type Server struct{}
+
+type Params struct {
+	Addr string `env:"ADDR"`
+}
+
+func NewServer(Params) *Server { return new(Server) }
+
+func Register(di *dix.Dix) {
+	dix.Provide(di, NewServer)
+}
@@ -0,0 +1,94 @@
+// Command dixgraph works with the graph snapshots of dix.Dix.Snapshot.
+//
+//	dixgraph diff [-json] old.json new.json
+//
+// diff prints the added, removed and changed providers and namespace keys,
+// it exits with status 1 when the wiring changed, so CI can compare a committed snapshot with a fresh one.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/pubgo/dix"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("dixgraph: ")
+
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: dixgraph diff [-json] old.json new.json")
+	}
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	switch cmd, args := flag.Arg(0), flag.Args()[1:]; cmd {
+	case "diff":
+		os.Exit(diff(args))
+	default:
+		log.Printf("unknown command %q", cmd)
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+func diff(args []string) int {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "print the diff as json")
+	_ = fs.Parse(args)
+
+	if fs.NArg() != 2 {
+		log.Println("diff needs two snapshot files")
+		return 2
+	}
+
+	a, err := load(fs.Arg(0))
+	if err != nil {
+		log.Println(err)
+		return 2
+	}
+
+	b, err := load(fs.Arg(1))
+	if err != nil {
+		log.Println(err)
+		return 2
+	}
+
+	d := dix.DiffGraphs(a, b)
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(d); err != nil {
+			log.Println(err)
+			return 2
+		}
+	} else {
+		fmt.Print(d)
+	}
+
+	if d.Empty() {
+		return 0
+	}
+	return 1
+}
+
+func load(path string) (*dix.GraphSnapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := dix.LoadSnapshot(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return s, nil
+}
@@ -0,0 +1,320 @@
+// Package analyzer reports dix providers and injection targets that dix rejects or mishandles at runtime.
+package analyzer
+
+import (
+	"go/ast"
+	"go/types"
+	"reflect"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+const injectMethodPrefix = "DixInject"
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "dixlint",
+	Doc:      "check dix.Provide and dix.Inject arguments which panic at runtime",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+type callKind int
+
+const (
+	provideCall callKind = iota
+	injectCall
+	injectGenericCall
+)
+
+type dixFunc struct {
+	kind     callKind
+	argIndex int
+}
+
+var dixFuncs = map[string]dixFunc{
+	"github.com/pubgo/dix.Provide":                    {provideCall, 0},
+	"github.com/pubgo/dix.Private":                    {provideCall, 0},
+	"github.com/pubgo/dix.Register":                   {provideCall, 2},
+	"github.com/pubgo/dix/dixglobal.Provide":          {provideCall, 0},
+	"(*github.com/pubgo/dix/dixinternal.Dix).Provide": {provideCall, 0},
+	"github.com/pubgo/dix.Inject":                     {injectGenericCall, 1},
+	"github.com/pubgo/dix/dixglobal.Inject":           {injectGenericCall, 0},
+	"(*github.com/pubgo/dix/dixinternal.Dix).Inject":  {injectCall, 0},
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	insp.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(node ast.Node) {
+		call := node.(*ast.CallExpr)
+		callee, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+		if !ok {
+			return
+		}
+
+		fn, ok := dixFuncs[callee.FullName()]
+		if !ok || fn.argIndex >= len(call.Args) {
+			return
+		}
+
+		// the provide funcs take every argument from argIndex as a provider,
+		// dix.Provide registers into the container of its first argument
+		args := call.Args[fn.argIndex : fn.argIndex+1]
+		if fn.kind == provideCall {
+			if call.Ellipsis.IsValid() {
+				return
+			}
+			args = call.Args[fn.argIndex:]
+			if isContainer(pass.TypesInfo.TypeOf(args[0])) {
+				args = args[1:]
+			}
+		}
+
+		for _, arg := range args {
+			typ := pass.TypesInfo.TypeOf(arg)
+			if typ == nil || isCondition(typ) {
+				continue
+			}
+
+			c := &checker{pass: pass, node: arg}
+			switch fn.kind {
+			case provideCall:
+				c.checkProvider(typ)
+			case injectCall:
+				c.checkInject(typ, false)
+			case injectGenericCall:
+				c.checkInject(typ, true)
+			}
+		}
+	})
+	return nil, nil
+}
+
+type checker struct {
+	pass *analysis.Pass
+	node ast.Node
+}
+
+func (c *checker) reportf(format string, args ...any) {
+	c.pass.Reportf(c.node.Pos(), "dix: "+format, args...)
+}
+
+// checkProvider mirrors the checks of provide and getProvideInput
+func (c *checker) checkProvider(typ types.Type) {
+	sig, ok := typ.Underlying().(*types.Signature)
+	if !ok {
+		c.reportf("provider should be function type, got %s", typ)
+		return
+	}
+
+	if sig.Variadic() {
+		c.reportf("variadic provider func is not allowed")
+	}
+
+	switch results := sig.Results(); {
+	case results.Len() == 0:
+		c.reportf("provider func should return a value")
+	case results.Len() > 2:
+		c.reportf("provider func should return at most 2 values, got %d", results.Len())
+	case results.Len() == 2 && !isError(results.At(1).Type()):
+		c.reportf("second result of provider func must be error, got %s", results.At(1).Type())
+	}
+
+	if sig.Results().Len() > 0 && !isSupportedOutput(sig.Results().At(0).Type()) {
+		c.reportf("provider output type is not supported, got %s", sig.Results().At(0).Type())
+	}
+
+	for i := 0; i < sig.Params().Len(); i++ {
+		param := sig.Params().At(i).Type()
+		if isValueKind(param) {
+			c.reportf("provider parameter %d of value type %s is dropped by dix, the call will panic", i, param)
+			continue
+		}
+		c.checkInput(param, "provider parameter")
+	}
+}
+
+func (c *checker) checkInject(typ types.Type, generic bool) {
+	switch u := typ.Underlying().(type) {
+	case *types.Signature:
+		c.checkInjectFunc(u, "inject func")
+	case *types.Pointer:
+		st, ok := u.Elem().Underlying().(*types.Struct)
+		if !ok {
+			c.reportf("inject param should be pointer to struct, got %s", typ)
+			return
+		}
+		c.checkInjectMethods(u.Elem())
+		c.checkInjectStruct(st)
+	case *types.Struct:
+		if !generic {
+			c.reportf("inject param should be pointer to struct, got %s, use &value or dix.Inject", typ)
+			return
+		}
+		c.checkInjectMethods(typ)
+		c.checkInjectStruct(u)
+	default:
+		c.reportf("inject param should be func or pointer to struct, got %s", typ)
+	}
+}
+
+// checkInjectFunc mirrors compileFuncPlan
+func (c *checker) checkInjectFunc(sig *types.Signature, name string) {
+	switch results := sig.Results(); {
+	case results.Len() > 1:
+		c.reportf("%s should return at most one value", name)
+	case results.Len() == 1 && !isError(results.At(0).Type()):
+		c.reportf("%s can only return error, got %s", name, results.At(0).Type())
+	}
+
+	if sig.Params().Len() == 0 {
+		c.reportf("%s should have parameters", name)
+	}
+
+	for i := 0; i < sig.Params().Len(); i++ {
+		c.checkInput(sig.Params().At(i).Type(), name+" parameter")
+	}
+}
+
+// checkInjectMethods mirrors compileMethodPlan, the methods are called on the injection target only
+func (c *checker) checkInjectMethods(typ types.Type) {
+	methods := types.NewMethodSet(types.NewPointer(typ))
+	for i := 0; i < methods.Len(); i++ {
+		fn, ok := methods.At(i).Obj().(*types.Func)
+		if !ok || !strings.HasPrefix(fn.Name(), injectMethodPrefix) {
+			continue
+		}
+		c.checkInjectFunc(fn.Type().(*types.Signature), "method "+fn.Name())
+	}
+}
+
+// checkInjectStruct mirrors compileStructPlan
+func (c *checker) checkInjectStruct(st *types.Struct) {
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+		if !field.Exported() {
+			continue
+		}
+
+		tag := reflect.StructTag(st.Tag(i))
+		if hasValueTag(tag) {
+			if !isParsableValue(field.Type()) {
+				c.reportf("field %s with env/flag/default tag has unsupported type %s", field.Name(), field.Type())
+			}
+			continue
+		}
+
+		if st, ok := field.Type().Underlying().(*types.Struct); ok {
+			c.checkInjectStruct(st)
+			continue
+		}
+		c.checkInput(field.Type(), "field "+field.Name())
+	}
+}
+
+func (c *checker) checkInput(typ types.Type, name string) {
+	switch u := typ.Underlying().(type) {
+	case *types.Pointer, *types.Interface, *types.Signature:
+	case *types.Struct:
+		c.checkInjectStruct(u)
+	case *types.Map:
+		elem := u.Elem()
+		if s, ok := elem.Underlying().(*types.Slice); ok {
+			elem = s.Elem()
+		}
+
+		if !isProvidable(elem) {
+			c.reportf("%s map value type is not supported, got %s", name, typ)
+		}
+	case *types.Slice:
+		if !isProvidable(u.Elem()) {
+			c.reportf("%s list element type is not supported, got %s", name, typ)
+		}
+	default:
+		c.reportf("%s type is not supported, got %s", name, typ)
+	}
+}
+
+func isProvidable(t types.Type) bool {
+	switch t.Underlying().(type) {
+	case *types.Pointer, *types.Interface, *types.Signature:
+		return true
+	default:
+		return false
+	}
+}
+
+func isSupportedOutput(t types.Type) bool {
+	switch u := t.Underlying().(type) {
+	case *types.Pointer, *types.Interface, *types.Signature, *types.Struct:
+		return true
+	case *types.Slice:
+		return isProvidable(u.Elem())
+	case *types.Map:
+		if s, ok := u.Elem().Underlying().(*types.Slice); ok {
+			return isProvidable(s.Elem())
+		}
+		return isProvidable(u.Elem())
+	default:
+		return false
+	}
+}
+
+func isValueKind(t types.Type) bool {
+	switch t.Underlying().(type) {
+	case *types.Basic, *types.Array, *types.Chan:
+		return true
+	default:
+		return false
+	}
+}
+
+// isParsableValue mirrors parseValue
+func isParsableValue(t types.Type) bool {
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		return u.Info()&(types.IsBoolean|types.IsInteger|types.IsFloat|types.IsString) != 0
+	case *types.Slice:
+		_, ok := u.Elem().Underlying().(*types.Basic)
+		return ok && isParsableValue(u.Elem())
+	default:
+		return false
+	}
+}
+
+func hasValueTag(tag reflect.StructTag) bool {
+	for _, name := range []string{"env", "flag", "default"} {
+		if _, ok := tag.Lookup(name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// isCondition reports the dix.If and dix.Profile arguments of the provide funcs
+func isCondition(t types.Type) bool {
+	named, ok := types.Unalias(t).(*types.Named)
+	return ok && named.Obj().Pkg() != nil &&
+		named.Obj().Pkg().Path() == "github.com/pubgo/dix/dixinternal" && named.Obj().Name() == "Condition"
+}
+
+// isContainer reports the *dix.Dix argument of dix.Provide
+func isContainer(t types.Type) bool {
+	ptr, ok := types.Unalias(t).(*types.Pointer)
+	if !ok {
+		return false
+	}
+
+	named, ok := types.Unalias(ptr.Elem()).(*types.Named)
+	return ok && named.Obj().Pkg() != nil &&
+		named.Obj().Pkg().Path() == "github.com/pubgo/dix/dixinternal" && named.Obj().Name() == "Dix"
+}
+
+var errorType = types.Universe.Lookup("error").Type().Underlying().(*types.Interface)
+
+func isError(t types.Type) bool {
+	return types.Implements(t, errorType)
+}
@@ -0,0 +1,98 @@
+package a
+
+import (
+	"github.com/pubgo/dix"
+	"github.com/pubgo/dix/dixglobal"
+)
+
+type (
+	A struct{}
+	B struct{}
+)
+
+type Target struct {
+	A  *A
+	As []*A
+	Bs map[string]*B
+}
+
+func (t *Target) DixInjectB(b *B) {}
+
+type Params struct {
+	A *A
+}
+
+// parameter objects are injected by their fields, the methods are not called
+func (p *Params) DixInjectNothing() {}
+
+type BadMethod struct{}
+
+func (b *BadMethod) DixInjectNothing() {}
+
+type BadTag struct {
+	Ch chan int `env:"CH"`
+}
+
+type BadField struct {
+	N int
+}
+
+func providers(di *dix.Dix) {
+	di.Provide(func() *A { return nil })
+	di.Provide(func(a *A, as []*A, bs map[string]*B, p Params) *B { return nil })
+	di.Provide(func() (*A, error) { return nil, nil })
+	di.Provide(func() map[string]*A { return nil })
+	di.Provide(func() *A { return nil }, dix.If(func(*B) bool { return true }))
+
+	di.Provide(new(A))                                          // want "provider should be function type"
+	di.Provide(func(as ...*A) *B { return nil })                // want "variadic provider func is not allowed"
+	di.Provide(func() {})                                       // want "provider func should return a value"
+	di.Provide(func() (*A, *B, error) { return nil, nil, nil }) // want "provider func should return at most 2 values, got 3"
+	di.Provide(func() (*A, int) { return nil, 0 })              // want "second result of provider func must be error, got int"
+	di.Provide(func() int { return 0 })                         // want "provider output type is not supported, got int"
+	di.Provide(func(n int) *A { return nil })                   // want "provider parameter 0 of value type int is dropped by dix"
+	di.Provide(func(m map[string]int) *A { return nil })        // want "provider parameter map value type is not supported"
+	di.Provide(func(s []int) *A { return nil })                 // want "provider parameter list element type is not supported"
+}
+
+func packageFuncs(di *dix.Dix) {
+	dix.Provide(di, func() *A { return nil })
+	dix.Provide(di, func() *A { return nil }, dix.If(func(*B) bool { return true }))
+	dix.Provide(di, func() int { return 0 }) // want "provider output type is not supported"
+	dix.Provide(di, new(A))                  // want "provider should be function type"
+
+	dixglobal.Provide(func() *A { return nil })
+	dixglobal.Provide(func() int { return 0 }) // want "provider output type is not supported"
+
+	dix.Provide(func() *A { return nil }, func() int { return 0 }) // want "provider output type is not supported"
+	dix.Private(func() {})                                         // want "provider func should return a value"
+
+	// the arguments of a slice are not known statically
+	fns := []any{func() int { return 0 }}
+	dix.Provide(fns...)
+
+	dix.Register("codec", "json", func() *A { return nil })
+	dix.Register("codec", "xml", func() int { return 0 }) // want "provider output type is not supported"
+}
+
+func injections(di *dix.Dix) {
+	di.Inject(func(a *A, as []*A, bs map[string]*B) {})
+	di.Inject(func(a *A) error { return nil })
+	di.Inject(new(Target))
+	dix.Inject(di, Target{})
+	dixglobal.Inject(func(a *A) {})
+
+	n := 0
+	di.Inject(func() {})                                   // want "inject func should have parameters"
+	di.Inject(func(*A) int { return 0 })                   // want "inject func can only return error, got int"
+	di.Inject(func(*A) (error, error) { return nil, nil }) // want "inject func should return at most one value"
+	di.Inject(func(n int) {})                              // want "inject func parameter type is not supported, got int"
+	di.Inject(&n)                                          // want "inject param should be pointer to struct"
+	di.Inject(Target{})                                    // want "use &value or dix.Inject"
+	di.Inject(42)                                          // want "inject param should be func or pointer to struct, got int"
+	di.Inject(new(BadMethod))                              // want "method DixInjectNothing should have parameters"
+	di.Inject(new(BadTag))                                 // want "field Ch with env/flag/default tag has unsupported type chan int"
+	di.Inject(new(BadField))                               // want "field N type is not supported, got int"
+	dixglobal.Inject(func() {})                            // want "inject func should have parameters"
+	dix.Inject(di, func(*A) int { return 0 })              // want "inject func can only return error, got int"
+}
@@ -0,0 +1,23 @@
+// Package dix declares the signatures dixlint matches, the bodies are not used.
+package dix
+
+import "github.com/pubgo/dix/dixinternal"
+
+type (
+	Dix          = dixinternal.Dix
+	Option       = dixinternal.Option
+	ModuleOption = dixinternal.ModuleOption
+	Condition    = dixinternal.Condition
+)
+
+func New(opts ...Option) *Dix { return new(Dix) }
+
+func Provide(args ...any) ModuleOption { return nil }
+
+func Private(fns ...any) ModuleOption { return nil }
+
+func Register(registry, name string, fn any) {}
+
+func Inject[T any](di *Dix, data T, opts ...Option) T { return data }
+
+func If(fn any) Condition { return dixinternal.If(fn) }
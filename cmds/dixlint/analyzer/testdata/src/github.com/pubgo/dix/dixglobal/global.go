@@ -0,0 +1,8 @@
+// Package dixglobal declares the signatures dixlint matches, the bodies are not used.
+package dixglobal
+
+import "github.com/pubgo/dix/dixinternal"
+
+func Provide(data any, conds ...dixinternal.Condition) {}
+
+func Inject[T any](data T, opts ...dixinternal.Option) T { return data }
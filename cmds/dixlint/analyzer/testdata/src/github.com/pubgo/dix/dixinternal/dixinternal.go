@@ -0,0 +1,15 @@
+// Package dixinternal declares the signatures dixlint matches, the bodies are not used.
+package dixinternal
+
+type (
+	Option       func()
+	ModuleOption func()
+	Condition    struct{}
+	Dix          struct{}
+)
+
+func (x *Dix) Provide(param any, conds ...Condition) {}
+
+func (x *Dix) Inject(param any, opts ...Option) any { return param }
+
+func If(fn any) Condition { return Condition{} }
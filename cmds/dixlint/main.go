@@ -0,0 +1,14 @@
+// Command dixlint checks dix.Provide and dix.Inject arguments which panic at runtime.
+//
+//	go build -o dixlint github.com/pubgo/dix/cmds/dixlint
+//	go vet -vettool=$(pwd)/dixlint ./...
+package main
+
+import (
+	"github.com/pubgo/dix/cmds/dixlint/analyzer"
+	"golang.org/x/tools/go/analysis/unitchecker"
+)
+
+func main() {
+	unitchecker.Main(analyzer.Analyzer)
+}
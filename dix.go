@@ -1,6 +1,8 @@
 package dix
 
 import (
+	"flag"
+	"log/slog"
 	"reflect"
 
 	"github.com/pubgo/dix/dixinternal"
@@ -10,17 +12,201 @@ const (
 	InjectMethodPrefix = dixinternal.InjectMethodPrefix
 )
 
+const (
+	NodeType      = dixinternal.NodeType
+	NodeProvider  = dixinternal.NodeProvider
+	NodeNamespace = dixinternal.NodeNamespace
+	NodeObject    = dixinternal.NodeObject
+
+	EdgeInput     = dixinternal.EdgeInput
+	EdgeOutput    = dixinternal.EdgeOutput
+	EdgeNamespace = dixinternal.EdgeNamespace
+	EdgeObject    = dixinternal.EdgeObject
+)
+
+const (
+	UnusedIgnore = dixinternal.UnusedIgnore
+	UnusedLog    = dixinternal.UnusedLog
+	UnusedFail   = dixinternal.UnusedFail
+)
+
+const (
+	FormatDOT      = dixinternal.FormatDOT
+	FormatMermaid  = dixinternal.FormatMermaid
+	FormatPlantUML = dixinternal.FormatPlantUML
+)
+
+const (
+	FocusAncestors   = dixinternal.FocusAncestors
+	FocusDescendants = dixinternal.FocusDescendants
+)
+
 type (
 	Option  = dixinternal.Option
 	Options = dixinternal.Options
 	Dix     = dixinternal.Dix
 	Graph   = dixinternal.Graph
+
+	Cycle     = dixinternal.Cycle
+	CycleEdge = dixinternal.CycleEdge
+
+	GraphModel   = dixinternal.GraphModel
+	GraphNode    = dixinternal.GraphNode
+	GraphEdge    = dixinternal.GraphEdge
+	ProviderInfo = dixinternal.ProviderInfo
+	NodeKind     = dixinternal.NodeKind
+	EdgeKind     = dixinternal.EdgeKind
+
+	GraphFormat  = dixinternal.GraphFormat
+	GraphOption  = dixinternal.GraphOption
+	GraphOptions = dixinternal.GraphOptions
+	Renderer     = dixinternal.Renderer
+
+	FakeFactory  = dixinternal.FakeFactory
+	Condition    = dixinternal.Condition
+	ModuleOption = dixinternal.ModuleOption
+	Plugin       = dixinternal.Plugin
+
+	GraphFocus     = dixinternal.GraphFocus
+	FocusDirection = dixinternal.FocusDirection
+
+	ProviderStat = dixinternal.ProviderStat
+	BootReport   = dixinternal.BootReport
+
+	Observer      = dixinternal.Observer
+	NopObserver   = dixinternal.NopObserver
+	ProviderEvent = dixinternal.ProviderEvent
+
+	Logger    = dixinternal.Logger
+	NopLogger = dixinternal.NopLogger
+
+	UnusedAction   = dixinternal.UnusedAction
+	UnusedReport   = dixinternal.UnusedReport
+	UnusedProvider = dixinternal.UnusedProvider
+	UnusedObject   = dixinternal.UnusedObject
+
+	Explanation    = dixinternal.Explanation
+	Candidate      = dixinternal.Candidate
+	ExplainedValue = dixinternal.ExplainedValue
+	DroppedValue   = dixinternal.DroppedValue
+
+	GraphSnapshot    = dixinternal.GraphSnapshot
+	SnapshotProvider = dixinternal.SnapshotProvider
+	GraphDiff        = dixinternal.GraphDiff
+	ProviderChange   = dixinternal.ProviderChange
+	NamespaceChange  = dixinternal.NamespaceChange
 )
 
 func WithValuesNull() Option {
 	return dixinternal.WithValuesNull()
 }
 
+// WithFlagSet resolves struct fields tagged with `flag:"name"` from fs
+func WithFlagSet(fs *flag.FlagSet) Option {
+	return dixinternal.WithFlagSet(fs)
+}
+
+// WithParallelInit lets Dix.Build evaluate up to n independent providers concurrently
+func WithParallelInit(n int) Option {
+	return dixinternal.WithParallelInit(n)
+}
+
+// WithRejectCycle makes Provide panic when the provider creates a dependency cycle
+func WithRejectCycle() Option {
+	return dixinternal.WithRejectCycle()
+}
+
+// Format selects the text format Dix.Graph renders, e.g. di.Graph(dix.Format(dix.FormatMermaid))
+func Format(format GraphFormat) GraphOption {
+	return dixinternal.Format(format)
+}
+
+// Ancestors renders only the dependencies of a type or provider func up to depth providers away, 0 means no limit,
+// e.g. di.Graph(dix.Ancestors("*main.Handler", 2), dix.ClusterByPackage(), dix.Highlight())
+func Ancestors(name string, depth int) GraphOption {
+	return dixinternal.Ancestors(name, depth)
+}
+
+// Descendants renders only the dependents of a type or provider func up to depth providers away, 0 means no limit
+func Descendants(name string, depth int) GraphOption {
+	return dixinternal.Descendants(name, depth)
+}
+
+// ClusterByPackage groups the providers of the rendered graphs by Go package
+func ClusterByPackage() GraphOption {
+	return dixinternal.ClusterByPackage()
+}
+
+// ClusterByModule groups the providers of the rendered graphs by the Module which registered them
+func ClusterByModule() GraphOption {
+	return dixinternal.ClusterByModule()
+}
+
+// Highlight colors the missing types, failed providers and nil producing providers of the rendered graphs
+func Highlight() GraphOption {
+	return dixinternal.Highlight()
+}
+
+// TypeNodeID returns the GraphModel node id of the type name qualified by the package path,
+// e.g. TypeNodeID("*main.DB") or TypeNodeID("*example.com/app/db.Client"), see TypeName
+func TypeNodeID(typ string) string {
+	return dixinternal.TypeNodeID(typ)
+}
+
+// TypeName returns the type name qualified by the package path, the type part of the GraphModel node ids
+func TypeName(typ reflect.Type) string {
+	return dixinternal.TypeName(typ)
+}
+
+// WithFakes turns on the test mode, interface types without providers are resolved to the fakes of factory
+func WithFakes(factory FakeFactory) Option {
+	return dixinternal.WithFakes(factory)
+}
+
+// WithObserver registers observers of the provider and injection lifecycle, e.g. for tracing or metrics
+func WithObserver(observers ...Observer) Option {
+	return dixinternal.WithObserver(observers...)
+}
+
+// WithLogger sets the logger of the container, by default it logs to the package logger of dixinternal.SetLog
+func WithLogger(log Logger) Option {
+	return dixinternal.WithLogger(log)
+}
+
+// WithLogLevel drops the log records of the container below level, e.g. slog.LevelError hides the "provider not found" warnings
+func WithLogLevel(level slog.Level) Option {
+	return dixinternal.WithLogLevel(level)
+}
+
+// WithSilentLog disables the logging of the container
+func WithSilentLog() Option {
+	return dixinternal.WithSilentLog()
+}
+
+// SlogLogger adapts a *slog.Logger to Logger
+func SlogLogger(l *slog.Logger) Logger {
+	return dixinternal.SlogLogger(l)
+}
+
+// WithUnusedCheck sets whether Dix.CheckUnused logs or fails on providers nobody depends on
+func WithUnusedCheck(action UnusedAction) Option {
+	return dixinternal.WithUnusedCheck(action)
+}
+
+// DiffGraphs reports the added, removed and changed providers and namespace keys from snapshot a to b
+//
+//	if diff := dix.DiffGraphs(committed, di.Snapshot()); !diff.Empty() {
+//		log.Fatal(diff)
+//	}
+func DiffGraphs(a, b *GraphSnapshot) *GraphDiff {
+	return dixinternal.DiffGraphs(a, b)
+}
+
+// LoadSnapshot decodes a snapshot written by GraphSnapshot.MarshalIndent
+func LoadSnapshot(data []byte) (*GraphSnapshot, error) {
+	return dixinternal.LoadSnapshot(data)
+}
+
 func New(opts ...Option) *Dix {
 	return dixinternal.New(opts...)
 }
@@ -36,6 +222,84 @@ func Inject[T any](di *Dix, data T, opts ...Option) T {
 	return data
 }
 
-func Provide(di *Dix, data any) {
-	di.Provide(data)
+// Explain tells how T resolves in namespace ns, see Dix.Explain
+//
+//	fmt.Println(dix.Explain[*Config](di, ""))
+func Explain[T any](di *Dix, ns string) *Explanation {
+	return di.Explain(reflect.TypeOf((*T)(nil)).Elem(), ns)
+}
+
+// If enables a provider when fn returns true, the parameters of fn are injected, e.g.
+//
+//	di.Provide(NewRedisCache, dix.If(func(cfg *Config) bool { return cfg.Cache == "redis" }))
+func If(fn any) Condition {
+	return dixinternal.If(fn)
+}
+
+// Profile enables a provider when one of the profiles is active, see WithProfiles
+func Profile(profiles ...string) Condition {
+	return dixinternal.Profile(profiles...)
+}
+
+// WithProfiles activates the profiles of the Profile conditions, e.g. dix.New(dix.WithProfiles(os.Getenv("APP_ENV")))
+func WithProfiles(profiles ...string) Option {
+	return dixinternal.WithProfiles(profiles...)
+}
+
+// Module groups providers and invocations, the returned Option installs it into New or Include
+//
+//	var Storage = dix.Module("storage",
+//		dix.Provide(NewDB, NewCache),
+//		dix.Private(NewPool),
+//		dix.Invoke(func(db *DB) { ... }),
+//		dix.Include(Config),
+//	)
+//
+//	di := dix.New(Storage, Server)
+func Module(name string, opts ...ModuleOption) Option {
+	return dixinternal.NewModule(name, opts...)
+}
+
+// Provide registers providers in a Module, the Condition arguments apply to every provider of the call.
+// With the container as the first argument it registers into the container right away, see Dix.Provide
+//
+//	dix.Provide(di, NewDB, dix.Profile("prod"))
+func Provide(args ...any) ModuleOption {
+	return dixinternal.Provide(args...)
+}
+
+// Private registers providers which are visible only in the Module
+func Private(fns ...any) ModuleOption {
+	return dixinternal.Private(fns...)
+}
+
+// Invoke injects the funcs or struct pointers in the scope of the Module after all modules are installed
+func Invoke(fns ...any) ModuleOption {
+	return dixinternal.Invoke(fns...)
+}
+
+// Include installs other modules in the Module, a module is installed once however often it is included
+func Include(modules ...Option) ModuleOption {
+	return dixinternal.Include(modules...)
+}
+
+// Register adds a named plugin to the registry, usually from init, the container enables it with WithPlugins
+// and injects the enabled plugins of a registry as map[string]T keyed by the plugin name
+//
+//	func init() { dix.Register("codec", "json", NewJSONCodec) }
+//
+//	di := dix.New(dix.WithPlugins("codec", cfg.Codecs...))
+//	dix.Inject(di, func(codecs map[string]Codec) { ... })
+func Register(registry, name string, fn any) {
+	dixinternal.Register(registry, name, fn)
+}
+
+// Plugins returns the plugins registered in the registry, sorted by name
+func Plugins(registry string) []*Plugin {
+	return dixinternal.Plugins(registry)
+}
+
+// WithPlugins enables the named plugins of the registry, New panics on a name which is not registered
+func WithPlugins(registry string, names ...string) Option {
+	return dixinternal.WithPlugins(registry, names...)
 }
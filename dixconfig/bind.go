@@ -0,0 +1,58 @@
+package dixconfig
+
+import (
+	"reflect"
+
+	"github.com/pubgo/dix"
+	"github.com/pubgo/funk/assert"
+	"github.com/pubgo/funk/errors"
+)
+
+// Provide registers the *Config loader with the container
+//
+//	dixconfig.Provide(di, dixconfig.WithFiles("config.yaml"), dixconfig.WithEnvPrefix("APP"))
+func Provide(di *dix.Dix, opts ...Option) {
+	di.Provide(func() (*Config, error) {
+		return Load(opts...)
+	})
+}
+
+// Bind registers a provider of T decoded from the config section at prefix, T must be a pointer to struct
+//
+//	dixconfig.Bind[*DBConfig](di, "db")
+func Bind[T any](di *dix.Dix, prefix string) {
+	typ := checkType[T]()
+	di.Provide(func(c *Config) (T, error) {
+		out := reflect.New(typ.Elem()).Interface().(T)
+		return out, c.Decode(prefix, out)
+	})
+}
+
+// BindMap registers a provider of map[string]T, every child key of the section at prefix is a namespace
+//
+//	db:
+//	  default: {host: localhost}
+//	  replica: {host: replica}
+//
+// The `default` namespace is also injectable as T.
+func BindMap[T any](di *dix.Dix, prefix string) {
+	typ := checkType[T]()
+	di.Provide(func(c *Config) (map[string]T, error) {
+		data := make(map[string]T)
+		for _, ns := range c.Keys(prefix) {
+			out := reflect.New(typ.Elem()).Interface().(T)
+			if err := c.Decode(prefix+"."+ns, out); err != nil {
+				return nil, errors.Wrapf(err, "failed to decode config namespace, namespace=%s", ns)
+			}
+			data[ns] = out
+		}
+		return data, nil
+	})
+}
+
+func checkType[T any]() reflect.Type {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	assert.If(typ.Kind() != reflect.Ptr || typ.Elem().Kind() != reflect.Struct,
+		"config type should be pointer to struct, type=%s", typ)
+	return typ
+}
@@ -0,0 +1,68 @@
+package dixconfig
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/pubgo/dix"
+)
+
+type dbConfig struct {
+	Host string `yaml:"host"`
+	Port int    `yaml:"port" default:"3306"`
+}
+
+func newConfigDix(t *testing.T, data string) *dix.Dix {
+	t.Helper()
+
+	di := dix.New(dix.WithSilentLog())
+	Provide(di, WithFiles(writeFile(t, "config.yaml", data)))
+	return di
+}
+
+func TestBind(t *testing.T) {
+	di := newConfigDix(t, "db:\n  host: localhost\n")
+	Bind[*dbConfig](di, "db")
+
+	di.Inject(func(c *dbConfig) {
+		if c.Host != "localhost" || c.Port != 3306 {
+			t.Fatalf("config=%+v", c)
+		}
+	})
+}
+
+func TestBindMap(t *testing.T) {
+	di := newConfigDix(t, "db:\n  default:\n    host: localhost\n  replica:\n    host: replica\n    port: 5432\n")
+	BindMap[*dbConfig](di, "db")
+
+	di.Inject(func(c *dbConfig, all map[string]*dbConfig) {
+		if c.Host != "localhost" {
+			t.Fatalf("the default namespace should be injectable, config=%+v", c)
+		}
+
+		if len(all) != 2 || all["replica"].Host != "replica" || all["replica"].Port != 5432 {
+			t.Fatalf("configs=%v", all)
+		}
+	})
+}
+
+func TestBindValidate(t *testing.T) {
+	di := newConfigDix(t, "db:\n  port: 5432\n")
+	Bind[*requiredConfig](di, "db")
+	BindMap[*requiredConfig](di, "db")
+
+	err := di.TryInject(func(*requiredConfig) {})
+	if err == nil || !strings.Contains(fmt.Sprint(err), "host is required") {
+		t.Fatalf("the validation error should fail the injection, err=%v", err)
+	}
+}
+
+func TestBindType(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("the config type should be a pointer to struct")
+		}
+	}()
+	Bind[dbConfig](dix.New(dix.WithSilentLog()), "db")
+}
@@ -0,0 +1,141 @@
+package dixconfig
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/pubgo/funk/errors"
+	"gopkg.in/yaml.v3"
+)
+
+type (
+	Option  func(opts *Options)
+	Options struct {
+		// Files are merged in order, later files override earlier ones
+		Files []string
+
+		// EnvPrefix is prepended to every environment variable name, e.g. APP -> APP_DB_HOST
+		EnvPrefix string
+	}
+)
+
+func WithFiles(files ...string) Option {
+	return func(opts *Options) {
+		opts.Files = append(opts.Files, files...)
+	}
+}
+
+func WithEnvPrefix(prefix string) Option {
+	return func(opts *Options) {
+		opts.EnvPrefix = prefix
+	}
+}
+
+// Config is the merged configuration tree loaded from yaml, json and toml files
+type Config struct {
+	envPrefix string
+	data      map[string]any
+}
+
+// Load reads all config files and returns the merged configuration
+func Load(opts ...Option) (*Config, error) {
+	var option Options
+	for i := range opts {
+		opts[i](&option)
+	}
+
+	c := &Config{
+		envPrefix: strings.Trim(strings.ToUpper(option.EnvPrefix), "_"),
+		data:      make(map[string]any),
+	}
+
+	for _, file := range option.Files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read config file, file=%s", file)
+		}
+
+		tree, err := unmarshal(filepath.Ext(file), data)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse config file, file=%s", file)
+		}
+
+		mergeTree(c.data, tree)
+	}
+
+	return c, nil
+}
+
+// Sub returns the section at the dot separated prefix, nil when it does not exist
+func (c *Config) Sub(prefix string) map[string]any {
+	if prefix == "" {
+		return c.data
+	}
+
+	cur := c.data
+	for _, key := range strings.Split(prefix, ".") {
+		next, ok := cur[key].(map[string]any)
+		if !ok {
+			return nil
+		}
+		cur = next
+	}
+	return cur
+}
+
+// Keys returns the sorted child keys of the section at prefix
+func (c *Config) Keys(prefix string) []string {
+	var keys []string
+	for k := range c.Sub(prefix) {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func unmarshal(ext string, data []byte) (map[string]any, error) {
+	var tree map[string]any
+	switch strings.ToLower(ext) {
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(data, &tree); err != nil {
+			return nil, err
+		}
+	case ".json":
+		if err := json.Unmarshal(data, &tree); err != nil {
+			return nil, err
+		}
+	case ".toml":
+		if err := toml.Unmarshal(data, &tree); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.Errorf("config file format not support, ext=%s", ext)
+	}
+
+	if tree == nil {
+		tree = make(map[string]any)
+	}
+	return tree, nil
+}
+
+// mergeTree deep merges src into dst, values in src win
+func mergeTree(dst, src map[string]any) {
+	for k, v := range src {
+		srcMap, ok := v.(map[string]any)
+		if !ok {
+			dst[k] = v
+			continue
+		}
+
+		dstMap, ok := dst[k].(map[string]any)
+		if !ok {
+			dstMap = make(map[string]any)
+			dst[k] = dstMap
+		}
+		mergeTree(dstMap, srcMap)
+	}
+}
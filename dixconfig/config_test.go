@@ -0,0 +1,98 @@
+package dixconfig
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeFile writes the config file into the temp dir of the test
+func writeFile(t *testing.T, name, data string) string {
+	t.Helper()
+
+	file := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(file, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestLoadFormats(t *testing.T) {
+	cases := []struct {
+		name string
+		data string
+	}{
+		{"config.yaml", "db:\n  host: localhost\n  port: 5432\n"},
+		{"config.yml", "db:\n  host: localhost\n  port: 5432\n"},
+		{"config.json", `{"db": {"host": "localhost", "port": 5432}}`},
+		{"config.toml", "[db]\nhost = \"localhost\"\nport = 5432\n"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cfg, err := Load(WithFiles(writeFile(t, c.name, c.data)))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := cfg.Keys("db"); !reflect.DeepEqual(got, []string{"host", "port"}) {
+				t.Fatalf("keys=%v", got)
+			}
+
+			var out struct {
+				Host string `yaml:"host"`
+				Port int    `yaml:"port"`
+			}
+			if err := cfg.Decode("db", &out); err != nil {
+				t.Fatal(err)
+			}
+
+			if out.Host != "localhost" || out.Port != 5432 {
+				t.Fatalf("out=%+v", out)
+			}
+		})
+	}
+}
+
+func TestLoadMerge(t *testing.T) {
+	base := writeFile(t, "base.yaml", "db:\n  host: localhost\n  port: 5432\nname: app\n")
+	local := writeFile(t, "local.json", `{"db": {"host": "replica"}}`)
+
+	cfg, err := Load(WithFiles(base, local))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db := cfg.Sub("db")
+	if db["host"] != "replica" || db["port"] != 5432 {
+		t.Fatalf("the later file should override the keys it sets only, db=%v", db)
+	}
+
+	if cfg.Sub("name") != nil || cfg.Sub("db.host") != nil || cfg.Sub("missing") != nil {
+		t.Fatal("a scalar or missing key is not a section")
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	cases := []struct {
+		name string
+		file string
+		want string
+	}{
+		{"missing", filepath.Join(t.TempDir(), "missing.yaml"), "failed to read config file"},
+		{"format", writeFile(t, "config.ini", "a=b"), "config file format not support"},
+		{"syntax", writeFile(t, "config.json", "{"), "failed to parse config file"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := Load(WithFiles(c.file))
+			if err == nil || !strings.Contains(fmt.Sprint(err), c.want) {
+				t.Fatalf("err=%v, want %q", err, c.want)
+			}
+		})
+	}
+}
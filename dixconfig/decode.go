@@ -0,0 +1,161 @@
+package dixconfig
+
+import (
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/pubgo/funk/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// Defaulter is called after `default` tags are applied and before the config section is decoded
+type Defaulter interface {
+	SetDefaults()
+}
+
+// Validator is called after the config section and environment variables are decoded
+type Validator interface {
+	Validate() error
+}
+
+// Decode decodes the section at prefix into out, out must be a pointer to struct.
+//
+// Order: `default` tags, Defaulter, config files, environment variables, Validator.
+// Struct fields are matched by their `yaml` tag, or the lower-cased field name.
+func (c *Config) Decode(prefix string, out any) error {
+	rv := reflect.ValueOf(out)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errors.Errorf("config out should be pointer to struct, type=%T", out)
+	}
+
+	if err := applyDefaults(rv.Elem()); err != nil {
+		return errors.Wrapf(err, "failed to apply config defaults, prefix=%s", prefix)
+	}
+
+	if d, ok := out.(Defaulter); ok {
+		d.SetDefaults()
+	}
+
+	if section := c.Sub(prefix); section != nil {
+		data, err := yaml.Marshal(section)
+		if err != nil {
+			return errors.Wrapf(err, "failed to encode config section, prefix=%s", prefix)
+		}
+
+		if err := yaml.Unmarshal(data, out); err != nil {
+			return errors.Wrapf(err, "failed to decode config section, prefix=%s type=%T", prefix, out)
+		}
+	}
+
+	if err := applyEnv(envName(c.envPrefix, prefix), rv.Elem()); err != nil {
+		return err
+	}
+
+	if v, ok := out.(Validator); ok {
+		if err := v.Validate(); err != nil {
+			return errors.Wrapf(err, "failed to validate config, prefix=%s type=%T", prefix, out)
+		}
+	}
+
+	return nil
+}
+
+func applyDefaults(vp reflect.Value) error {
+	tp := vp.Type()
+	for i := 0; i < tp.NumField(); i++ {
+		field := tp.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		if isNestedStruct(field.Type) {
+			if err := applyDefaults(vp.Field(i)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		def, ok := field.Tag.Lookup("default")
+		if !ok || !vp.Field(i).IsZero() {
+			continue
+		}
+
+		if err := setString(vp.Field(i), def); err != nil {
+			return errors.Wrapf(err, "failed to parse default value, field=%s value=%q", field.Name, def)
+		}
+	}
+	return nil
+}
+
+func applyEnv(prefix string, vp reflect.Value) error {
+	tp := vp.Type()
+	for i := 0; i < tp.NumField(); i++ {
+		field := tp.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		key := fieldKey(field)
+		if key == "-" {
+			continue
+		}
+
+		name := envName(prefix, key)
+		if isNestedStruct(field.Type) {
+			if err := applyEnv(name, vp.Field(i)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		val, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+
+		if err := setString(vp.Field(i), val); err != nil {
+			return errors.Wrapf(err, "failed to parse env, env=%s value=%q", name, val)
+		}
+	}
+	return nil
+}
+
+// setString parses the string as a yaml scalar or flow sequence, e.g. `8080`, `true`, `1s` or `a,b`
+func setString(field reflect.Value, val string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(val)
+		return nil
+	case reflect.Slice:
+		if !strings.HasPrefix(strings.TrimSpace(val), "[") {
+			val = "[" + val + "]"
+		}
+	}
+
+	return yaml.Unmarshal([]byte(val), field.Addr().Interface())
+}
+
+func fieldKey(field reflect.StructField) string {
+	tag := strings.Split(field.Tag.Get("yaml"), ",")[0]
+	if tag != "" {
+		return tag
+	}
+	return strings.ToLower(field.Name)
+}
+
+func envName(prefix, key string) string {
+	key = strings.NewReplacer(".", "_", "-", "_").Replace(strings.ToUpper(key))
+	switch {
+	case prefix == "":
+		return key
+	case key == "":
+		return prefix
+	default:
+		return prefix + "_" + key
+	}
+}
+
+func isNestedStruct(typ reflect.Type) bool {
+	return typ.Kind() == reflect.Struct && typ.PkgPath() != "time"
+}
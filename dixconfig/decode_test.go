@@ -0,0 +1,143 @@
+package dixconfig
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type orderConfig struct {
+	Host    string        `yaml:"host" default:"default"`
+	Port    int           `yaml:"port" default:"3306"`
+	User    string        `yaml:"user"`
+	Timeout time.Duration `yaml:"timeout" default:"1s"`
+	Tags    []string      `yaml:"tags"`
+	Pool    struct {
+		Size int `yaml:"size" default:"4"`
+	} `yaml:"pool"`
+
+	steps []string
+}
+
+func (c *orderConfig) SetDefaults() {
+	c.steps = append(c.steps, "defaulter host="+c.Host)
+	c.User = "defaulter"
+	c.Port = 1
+}
+
+func (c *orderConfig) Validate() error {
+	c.steps = append(c.steps, "validator host="+c.Host)
+	if c.User == "" {
+		return errors.New("user is required")
+	}
+	return nil
+}
+
+func TestDecodeOrder(t *testing.T) {
+	cfg, err := Load(WithFiles(writeFile(t, "config.yaml", "db:\n  host: yaml\n  port: 5432\n")), WithEnvPrefix("app_"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("APP_DB_HOST", "env")
+	t.Setenv("APP_DB_TAGS", "a,b")
+	t.Setenv("APP_DB_POOL_SIZE", "8")
+
+	var out orderConfig
+	if err := cfg.Decode("db", &out); err != nil {
+		t.Fatal(err)
+	}
+
+	// the defaulter sees the default tags, the validator sees the env
+	want := []string{"defaulter host=default", "validator host=env"}
+	if !reflect.DeepEqual(out.steps, want) {
+		t.Fatalf("steps=%v, want %v", out.steps, want)
+	}
+
+	// default tag < Defaulter < yaml < env
+	if out.Host != "env" || out.Port != 5432 || out.User != "defaulter" || out.Timeout != time.Second {
+		t.Fatalf("out=%+v", out)
+	}
+
+	if !reflect.DeepEqual(out.Tags, []string{"a", "b"}) || out.Pool.Size != 8 {
+		t.Fatalf("the env should set the list and the nested field, tags=%v size=%d", out.Tags, out.Pool.Size)
+	}
+}
+
+func TestDecodeMissingSection(t *testing.T) {
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a missing section keeps the defaults and still runs the validator
+	var out orderConfig
+	if err := cfg.Decode("db", &out); err != nil {
+		t.Fatal(err)
+	}
+
+	if out.Host != "default" || out.Port != 1 || out.Pool.Size != 4 {
+		t.Fatalf("out=%+v", out)
+	}
+}
+
+type requiredConfig struct {
+	Host string `yaml:"host"`
+}
+
+func (c *requiredConfig) Validate() error {
+	if c.Host == "" {
+		return errors.New("host is required")
+	}
+	return nil
+}
+
+func TestDecodeErrors(t *testing.T) {
+	cfg, err := Load(WithFiles(writeFile(t, "config.yaml", "db:\n  port: abc\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var port struct {
+		Port int `yaml:"port"`
+	}
+
+	var badDefault struct {
+		Port int `default:"abc"`
+	}
+
+	cases := []struct {
+		name string
+		out  any
+		want string
+	}{
+		{"not pointer", port, "config out should be pointer to struct"},
+		{"validation", new(requiredConfig), "host is required"},
+		{"section", &port, "failed to decode config section"},
+		{"default", &badDefault, "failed to parse default value"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := cfg.Decode("db", c.out)
+			if err == nil || !strings.Contains(fmt.Sprint(err), c.want) {
+				t.Fatalf("err=%v, want %q", err, c.want)
+			}
+		})
+	}
+
+	t.Run("env", func(t *testing.T) {
+		t.Setenv("HOST_PORT", "abc")
+
+		var out struct {
+			Port int `yaml:"port"`
+		}
+		err := cfg.Decode("host", &out)
+		if err == nil || !strings.Contains(fmt.Sprint(err), "failed to parse env, env=HOST_PORT") {
+			t.Fatalf("err=%v", err)
+		}
+	})
+}
@@ -20,9 +20,9 @@ var _dix = dixinternal.New(dixinternal.WithValuesNull())
 //
 // For more usage details, see the documentation for the Container type.
 
-// Provide registers an object constructor
-func Provide(data any) {
-	_dix.Provide(data)
+// Provide registers an object constructor, the conditions decide at evaluation time whether it provides values
+func Provide(data any, conds ...dixinternal.Condition) {
+	_dix.Provide(data, conds...)
 }
 
 // Inject injects objects
@@ -39,6 +39,11 @@ func Inject[T any](data T, opts ...dixinternal.Option) T {
 }
 
 // Graph Dix graph
-func Graph() *dixinternal.Graph {
-	return _dix.Graph()
+func Graph(opts ...dixinternal.GraphOption) *dixinternal.Graph {
+	return _dix.Graph(opts...)
+}
+
+// Inspect Dix graph model
+func Inspect() *dixinternal.GraphModel {
+	return _dix.Inspect()
 }
@@ -0,0 +1,187 @@
+// Package dixhttp serves a debug view of a live container, mount it like net/http/pprof
+//
+//	mux.Handle("/debug/dix/", http.StripPrefix("/debug/dix", dixhttp.Handler(di)))
+//
+// Endpoints, relative to the mount point:
+//
+//	/                      html page with the interactive graph
+//	/api/summary           provider, type and object counts, init cost and cycle status
+//	/api/providers         providers with source location, init state and cost
+//	/api/objects           instantiated objects per type and namespace
+//	/api/cycles            dependency cycles
+//	/api/stats             initialization metrics of the evaluated providers
+//	/api/boot              boot report with the critical path
+//	/api/graph             the graph model
+//	/api/snapshot          the stable graph snapshot, see dix.DiffGraphs
+//	/api/graph.txt?format= the graph rendered as dot, mermaid or plantuml
+//
+// /api/graph.txt also takes graph=providers|types|objects, ancestors=<name>, descendants=<name>,
+// depth=<n>, cluster=1 and highlight=1, see dix.Ancestors, dix.ClusterByPackage and dix.Highlight
+package dixhttp
+
+import (
+	_ "embed"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pubgo/dix"
+)
+
+//go:embed index.html
+var indexHTML []byte
+
+type Summary struct {
+	Providers   int           `json:"providers"`
+	Initialized int           `json:"initialized"`
+	Failed      int           `json:"failed"`
+	Types       int           `json:"types"`
+	Objects     int           `json:"objects"`
+	InitCost    time.Duration `json:"init_cost"`
+	HasCycle    bool          `json:"has_cycle"`
+}
+
+type Provider struct {
+	ID string `json:"id"`
+	*dix.ProviderInfo
+}
+
+type Objects struct {
+	Type      string   `json:"type"`
+	Namespace string   `json:"namespace"`
+	Values    []string `json:"values"`
+}
+
+// Handler returns the debug handler of the container
+func Handler(di *dix.Dix) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/{$}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write(indexHTML)
+	})
+
+	mux.HandleFunc("/api/summary", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, summary(di.Inspect(), len(di.Cycles()) > 0))
+	})
+
+	mux.HandleFunc("/api/providers", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, providers(di.Inspect()))
+	})
+
+	mux.HandleFunc("/api/objects", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, objects(di.Inspect()))
+	})
+
+	mux.HandleFunc("/api/cycles", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, di.Cycles())
+	})
+
+	mux.HandleFunc("/api/stats", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, di.Stats())
+	})
+
+	mux.HandleFunc("/api/boot", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, di.BootReport())
+	})
+
+	mux.HandleFunc("/api/graph", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, di.Inspect())
+	})
+
+	mux.HandleFunc("/api/snapshot", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, di.Snapshot())
+	})
+
+	mux.HandleFunc("/api/graph.txt", func(w http.ResponseWriter, r *http.Request) {
+		format := dix.GraphFormat(r.URL.Query().Get("format"))
+		switch format {
+		case "":
+			format = dix.FormatDOT
+		case dix.FormatDOT, dix.FormatMermaid, dix.FormatPlantUML:
+		default:
+			http.Error(w, "unknown format "+string(format), http.StatusBadRequest)
+			return
+		}
+
+		query := r.URL.Query()
+		opts := []dix.GraphOption{dix.Format(format)}
+		depth, _ := strconv.Atoi(query.Get("depth"))
+		if name := query.Get("ancestors"); name != "" {
+			opts = append(opts, dix.Ancestors(name, depth))
+		}
+		if name := query.Get("descendants"); name != "" {
+			opts = append(opts, dix.Descendants(name, depth))
+		}
+		if query.Get("cluster") == "1" {
+			opts = append(opts, dix.ClusterByPackage())
+		}
+		if query.Get("highlight") == "1" {
+			opts = append(opts, dix.Highlight())
+		}
+
+		g := di.Graph(opts...)
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		switch query.Get("graph") {
+		case "objects":
+			_, _ = w.Write([]byte(g.Objects))
+		case "types":
+			_, _ = w.Write([]byte(g.ProviderTypes))
+		default:
+			_, _ = w.Write([]byte(g.Providers))
+		}
+	})
+
+	return mux
+}
+
+func summary(g *dix.GraphModel, hasCycle bool) *Summary {
+	s := &Summary{HasCycle: hasCycle}
+	for _, n := range g.Nodes {
+		switch n.Kind {
+		case dix.NodeType:
+			s.Types++
+		case dix.NodeObject:
+			s.Objects++
+		case dix.NodeProvider:
+			s.Providers++
+			s.InitCost += n.Provider.Cost
+			if n.Provider.Initialized {
+				s.Initialized++
+			}
+			if n.Provider.Error != "" {
+				s.Failed++
+			}
+		}
+	}
+	return s
+}
+
+func providers(g *dix.GraphModel) []*Provider {
+	var list []*Provider
+	for _, n := range g.NodesOf(dix.NodeProvider) {
+		list = append(list, &Provider{ID: n.ID, ProviderInfo: n.Provider})
+	}
+	return list
+}
+
+func objects(g *dix.GraphModel) []*Objects {
+	var list []*Objects
+	for _, ns := range g.NodesOf(dix.NodeNamespace) {
+		objs := &Objects{Type: ns.Type, Namespace: ns.Namespace}
+		for _, e := range g.EdgesFrom(ns.ID) {
+			objs.Values = append(objs.Values, g.Node(e.To).Label)
+		}
+		list = append(list, objs)
+	}
+	return list
+}
+
+func writeJSON(w http.ResponseWriter, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
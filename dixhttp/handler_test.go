@@ -0,0 +1,145 @@
+package dixhttp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pubgo/dix"
+)
+
+type (
+	testDB    struct{}
+	testCache struct{}
+	testQueue struct{}
+)
+
+func newTestHandler(t *testing.T) http.Handler {
+	t.Helper()
+
+	di := dix.New(dix.WithSilentLog())
+	di.Provide(func() *testDB { return new(testDB) })
+	di.Provide(func(*testDB) *testCache { return new(testCache) })
+	di.Provide(func() *testQueue { return new(testQueue) })
+	di.Inject(func(*testCache) {})
+	return Handler(di)
+}
+
+func get(t *testing.T, h http.Handler, url string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+	return w
+}
+
+func TestHandlerGraphFormat(t *testing.T) {
+	h := newTestHandler(t)
+	for _, tt := range []struct {
+		url, want string
+	}{
+		{"/api/graph.txt", "digraph"},
+		{"/api/graph.txt?format=dot", "digraph"},
+		{"/api/graph.txt?format=mermaid", "flowchart"},
+		{"/api/graph.txt?format=plantuml", "@startuml"},
+		{"/api/graph.txt?format=mermaid&graph=types", "flowchart"},
+		{"/api/graph.txt?format=mermaid&graph=objects", "flowchart"},
+	} {
+		w := get(t, h, tt.url)
+		if w.Code != http.StatusOK || !strings.HasPrefix(w.Body.String(), tt.want) {
+			t.Fatalf("%s: code=%d body=%s", tt.url, w.Code, w.Body)
+		}
+	}
+
+	if w := get(t, h, "/api/graph.txt?format=svg"); w.Code != http.StatusBadRequest {
+		t.Fatalf("an unknown format should be rejected, code=%d", w.Code)
+	}
+}
+
+func TestHandlerGraphFocus(t *testing.T) {
+	h := newTestHandler(t)
+
+	body := get(t, h, "/api/graph.txt?format=mermaid&graph=types&ancestors=*dixhttp.testCache").Body.String()
+	if !strings.Contains(body, "*dixhttp.testDB") || strings.Contains(body, "testQueue") {
+		t.Fatalf("ancestors should render the dependencies only, body=%s", body)
+	}
+
+	body = get(t, h, "/api/graph.txt?format=mermaid&graph=types&descendants=*dixhttp.testDB&depth=1").Body.String()
+	if !strings.Contains(body, "*dixhttp.testCache") || strings.Contains(body, "testQueue") {
+		t.Fatalf("descendants should render the dependents only, body=%s", body)
+	}
+
+	body = get(t, h, "/api/graph.txt?cluster=1&highlight=1").Body.String()
+	if !strings.Contains(body, `label="github.com/pubgo/dix/dixhttp"`) {
+		t.Fatalf("cluster should group the providers by package, body=%s", body)
+	}
+}
+
+func TestHandlerAPI(t *testing.T) {
+	h := newTestHandler(t)
+
+	var stats []dix.ProviderStat
+	decode(t, get(t, h, "/api/stats"), &stats)
+	if len(stats) != 2 {
+		t.Fatalf("the evaluated providers should be reported, stats=%d", len(stats))
+	}
+
+	var boot dix.BootReport
+	decode(t, get(t, h, "/api/boot"), &boot)
+	if len(boot.CriticalPath) != 2 {
+		t.Fatalf("the critical path should go from db to cache, path=%d", len(boot.CriticalPath))
+	}
+
+	// the container provides itself too
+	var s Summary
+	decode(t, get(t, h, "/api/summary"), &s)
+	if s.Providers != 4 || s.Initialized != 2 || s.Types != 4 || s.HasCycle {
+		t.Fatalf("summary=%+v", s)
+	}
+
+	var list []*Provider
+	decode(t, get(t, h, "/api/providers"), &list)
+	if len(list) != 4 || list[0].Location == "" {
+		t.Fatalf("the providers should be listed with locations, providers=%d", len(list))
+	}
+
+	var g dix.GraphModel
+	decode(t, get(t, h, "/api/graph"), &g)
+	if g.Node(dix.TypeNodeID("*github.com/pubgo/dix/dixhttp.testCache")) == nil {
+		t.Fatal("the graph model should have the type nodes")
+	}
+
+	var snapshot dix.GraphSnapshot
+	decode(t, get(t, h, "/api/snapshot"), &snapshot)
+	if len(snapshot.Providers) != 4 || len(snapshot.Namespaces) == 0 {
+		t.Fatalf("the snapshot should have the providers and namespaces, snapshot=%+v", snapshot)
+	}
+
+	w := get(t, h, "/")
+	if w.Code != http.StatusOK || !strings.HasPrefix(w.Header().Get("Content-Type"), "text/html") {
+		t.Fatalf("the index should be served, code=%d", w.Code)
+	}
+}
+
+func TestHandlerNotFound(t *testing.T) {
+	h := newTestHandler(t)
+	for _, url := range []string{"/index.html", "/api/unknown", "/api/graph.svg"} {
+		if w := get(t, h, url); w.Code != http.StatusNotFound {
+			t.Fatalf("%s: code=%d", url, w.Code)
+		}
+	}
+}
+
+func decode(t *testing.T, w *httptest.ResponseRecorder, v any) {
+	t.Helper()
+
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf("code=%d content-type=%s", w.Code, w.Header().Get("Content-Type"))
+	}
+
+	if err := json.NewDecoder(w.Body).Decode(v); err != nil {
+		t.Fatal(err)
+	}
+}
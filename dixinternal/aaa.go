@@ -21,9 +21,10 @@ type (
 )
 
 type Graph struct {
-	Objects       string `json:"objects"`
-	Providers     string `json:"providers"`
-	ProviderTypes string `json:"provider_types"`
+	Format        GraphFormat `json:"format"`
+	Objects       string      `json:"objects"`
+	Providers     string      `json:"providers"`
+	ProviderTypes string      `json:"provider_types"`
 }
 
 var logger = log.GetLogger("dix")
@@ -1,6 +1,8 @@
 package dixinternal
 
 import (
+	"context"
+	"fmt"
 	"reflect"
 
 	"github.com/pubgo/funk/assert"
@@ -12,16 +14,17 @@ func New(opts ...Option) *Dix {
 	return newDix(opts...)
 }
 
-func (x *Dix) Provide(param any) {
-	x.provide(param)
+// Provide registers the provider, the conditions decide at evaluation time whether it provides values, see If and Profile
+func (x *Dix) Provide(param any, conds ...Condition) {
+	x.provideIn(param, providerScope{conds: conds})
 }
 
 func (x *Dix) Inject(param any, opts ...Option) any {
-	if dep, ok := x.isCycle(); ok {
-		logger.Error().
-			Str("cycle_path", dep).
-			Str("component", reflect.TypeOf(param).String()).
-			Msg("dependency cycle detected")
+	if _, ok := x.isCycle(); ok {
+		dep := x.cycleReport()
+		x.log.Error("dependency cycle detected",
+			"cycle_path", dep,
+			"component", reflect.TypeOf(param).String())
 		assert.Must(errors.New("circular dependency: " + dep))
 	}
 
@@ -29,10 +32,55 @@ func (x *Dix) Inject(param any, opts ...Option) any {
 	return param
 }
 
-func (x *Dix) Graph() *Graph {
+// TryInject is Inject returning the error instead of panicking
+func (x *Dix) TryInject(param any, opts ...Option) error {
+	if _, ok := x.isCycle(); ok {
+		return errors.New("circular dependency: " + x.cycleReport())
+	}
+
+	return x.inject(param, opts...).GetErr()
+}
+
+// Cycles returns every dependency cycle, one per strongly connected component, in a stable order
+func (x *Dix) Cycles() []Cycle {
+	return x.cycles()
+}
+
+// Build eagerly evaluates all providers in dependency order, see WithParallelInit
+func (x *Dix) Build(ctx context.Context) error {
+	return x.build(ctx, x.option.Parallelism)
+}
+
+// Graph renders the dependency graphs, in DOT format by default, see Format.
+// Ancestors and Descendants render only the wiring around a type or provider,
+// ClusterByPackage and Highlight make it readable, e.g. on a slide
+func (x *Dix) Graph(opts ...GraphOption) *Graph {
+	var opt GraphOptions
+	for i := range opts {
+		opts[i](&opt)
+	}
+
+	if opt.Format == "" {
+		opt.Format = FormatDOT
+	}
+
+	g := focus(x.Inspect(), opt.Focus)
+	if len(opt.Focus) > 0 && len(g.Nodes) == 0 {
+		x.log.Warn("graph focus matches no type or provider", "focus", fmt.Sprintf("%v", opt.Focus))
+	}
+
 	return &Graph{
-		Objects:       x.objectGraph(),
-		Providers:     x.providerGraph(),
-		ProviderTypes: x.providerGraphTypes(),
+		Format:        opt.Format,
+		Objects:       objectGraph(g, NewRenderer(opt.Format)),
+		Providers:     providerGraph(g, NewRenderer(opt.Format), &opt),
+		ProviderTypes: providerGraphTypes(g, NewRenderer(opt.Format), &opt),
 	}
 }
+
+// Inspect returns the dependency graph with the providers, namespaces and instantiated objects
+func (x *Dix) Inspect() *GraphModel {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	return x.inspect()
+}
@@ -0,0 +1,155 @@
+package dixinternal
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pubgo/funk/errors"
+)
+
+var errDependencyFailed = errors.New("dependency provider failed")
+
+type buildNode struct {
+	outTyp     outputType
+	provider   *providerFn
+	deps       []*buildNode
+	dependents []*buildNode
+	pending    int
+	err        error
+}
+
+// buildProviderDAG groups the providers by func and links each one to the providers of its inputs and condition inputs
+func (x *Dix) buildProviderDAG() []*buildNode {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	// a struct output provider is listed under every field type, the first type in sorted order names it
+	var outTypes []outputType
+	for outTyp := range x.providers {
+		outTypes = append(outTypes, outTyp)
+	}
+	sortTypes(outTypes)
+
+	nodes := make(map[reflect.Value]*buildNode)
+	for _, outTyp := range outTypes {
+		for _, n := range x.providers[outTyp] {
+			if _, ok := nodes[n.fn]; ok || x.initializer[n.fn] {
+				continue
+			}
+			nodes[n.fn] = &buildNode{outTyp: outTyp, provider: n}
+		}
+	}
+
+	for _, node := range nodes {
+		deps := make(map[reflect.Value]bool)
+		for _, in := range x.providerInputs(node.provider) {
+			for _, input := range getProvideAllInputs(x.log, in.typ) {
+				for _, dep := range x.providers[input.typ] {
+					if dep.fn == node.provider.fn || deps[dep.fn] || nodes[dep.fn] == nil {
+						continue
+					}
+
+					deps[dep.fn] = true
+					node.deps = append(node.deps, nodes[dep.fn])
+					nodes[dep.fn].dependents = append(nodes[dep.fn].dependents, node)
+				}
+			}
+		}
+	}
+
+	var list []*buildNode
+	for _, node := range nodes {
+		list = append(list, node)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].provider.seq < list[j].provider.seq })
+	return list
+}
+
+// build evaluates all providers on a pool of workers goroutines, a provider is queued when all its dependencies are done.
+// When several providers fail, the error of the earliest registered one is returned.
+func (x *Dix) build(ctx context.Context, workers int) error {
+	if _, ok := x.isCycle(); ok {
+		return errors.New("circular dependency: " + x.cycleReport())
+	}
+
+	nodes := x.buildProviderDAG()
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	workers = max(1, min(workers, len(nodes)))
+	ready := make(chan *buildNode, len(nodes))
+	for _, node := range nodes {
+		node.pending = len(node.deps)
+		if node.pending == 0 {
+			ready <- node
+		}
+	}
+
+	var mu sync.Mutex
+	var canceled atomic.Bool
+	remaining := len(nodes)
+	done := make(chan struct{})
+
+	// finish releases the dependents of the node, a dependent of a failed node fails without being evaluated
+	finish := func(node *buildNode) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		for _, dep := range node.dependents {
+			if node.err != nil && dep.err == nil {
+				dep.err = errDependencyFailed
+			}
+
+			dep.pending--
+			if dep.pending == 0 {
+				ready <- dep
+			}
+		}
+
+		remaining--
+		if remaining == 0 {
+			close(done)
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				case node := <-ready:
+					switch {
+					case node.err != nil:
+					case ctx.Err() != nil:
+						node.err = ctx.Err()
+						canceled.Store(true)
+					default:
+						node.err = x.evalProvider(node.outTyp, node.provider, x.option).GetErr()
+					}
+					finish(node)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// the build succeeded when every provider was evaluated, even if ctx is canceled afterwards
+	if canceled.Load() {
+		return errors.Wrap(ctx.Err(), "dix build canceled")
+	}
+
+	for _, node := range nodes {
+		if node.err != nil && node.err != errDependencyFailed {
+			return node.err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package dixinternal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type (
+	testDB    struct{}
+	testKafka struct{}
+	testCache struct{}
+)
+
+type testPair struct {
+	DB    *testDB
+	Cache *testCache
+}
+
+// rendezvous blocks every caller until n callers arrived, or fails the test when they never do
+type rendezvous struct {
+	t       *testing.T
+	arrived atomic.Int32
+	n       int32
+	all     chan struct{}
+	once    sync.Once
+}
+
+func newRendezvous(t *testing.T, n int32) *rendezvous {
+	return &rendezvous{t: t, n: n, all: make(chan struct{})}
+}
+
+func (r *rendezvous) wait() {
+	if r.arrived.Add(1) == r.n {
+		r.once.Do(func() { close(r.all) })
+	}
+
+	select {
+	case <-r.all:
+	case <-time.After(5 * time.Second):
+		r.t.Error("the providers are not evaluated concurrently")
+	}
+}
+
+func TestBuildParallel(t *testing.T) {
+	r := newRendezvous(t, 3)
+	di := New(WithSilentLog(), WithParallelInit(3))
+	di.Provide(func() *testDB { r.wait(); return new(testDB) })
+	di.Provide(func() *testRedis { r.wait(); return new(testRedis) })
+	di.Provide(func() *testKafka { r.wait(); return new(testKafka) })
+
+	if err := di.Build(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBuildParallelLimit(t *testing.T) {
+	var running, peak atomic.Int32
+	provider := func() {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			old := peak.Load()
+			if n <= old || peak.CompareAndSwap(old, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	di := New(WithSilentLog(), WithParallelInit(2))
+	di.Provide(func() *testDB { provider(); return new(testDB) })
+	di.Provide(func() *testRedis { provider(); return new(testRedis) })
+	di.Provide(func() *testKafka { provider(); return new(testKafka) })
+	di.Provide(func() *testCache { provider(); return new(testCache) })
+
+	if err := di.Build(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if peak.Load() > 2 {
+		t.Fatalf("at most 2 providers should run at once, peak=%d", peak.Load())
+	}
+}
+
+func TestBuildDependencyOrder(t *testing.T) {
+	var db atomic.Bool
+	di := New(WithSilentLog(), WithParallelInit(4))
+	di.Provide(func(*testDB) *testCache {
+		if !db.Load() {
+			t.Error("the dependency should be built first")
+		}
+		return new(testCache)
+	})
+	di.Provide(func() *testDB { db.Store(true); return new(testDB) })
+
+	if err := di.Build(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBuildWorkerPool(t *testing.T) {
+	var peak atomic.Int32
+	di := New(WithSilentLog(), WithParallelInit(2))
+	for i := 0; i < 50; i++ {
+		di.Provide(func() *testRedis {
+			n := int32(runtime.NumGoroutine())
+			for old := peak.Load(); n > old && !peak.CompareAndSwap(old, n); old = peak.Load() {
+			}
+			return &testRedis{Addr: fmt.Sprint(i)}
+		})
+	}
+
+	base := runtime.NumGoroutine()
+	if err := di.Build(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// the providers run on 2 workers, not on a goroutine each
+	if peak.Load() > int32(base+2) {
+		t.Fatalf("too many goroutines, base=%d peak=%d", base, peak.Load())
+	}
+}
+
+func TestBuildCanceledAfterDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	di := New(WithSilentLog(), WithParallelInit(2))
+	di.Provide(func() *testDB { return new(testDB) })
+	di.Provide(func(*testDB) *testCache { cancel(); return new(testCache) })
+
+	if err := di.Build(ctx); err != nil {
+		t.Fatalf("every provider was evaluated before the cancel, err=%v", err)
+	}
+}
+
+func TestBuildCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	di := New(WithSilentLog())
+	di.Provide(func() *testDB {
+		t.Error("the provider should not be evaluated")
+		return new(testDB)
+	})
+
+	if err := di.Build(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("err=%v", err)
+	}
+}
+
+func TestBuildDependencyFailed(t *testing.T) {
+	di := New(WithSilentLog(), WithParallelInit(2))
+	di.Provide(func() (*testDB, error) { return nil, errors.New("db is down") })
+	di.Provide(func(*testDB) *testCache {
+		t.Error("the dependent of the failed provider should not be evaluated")
+		return new(testCache)
+	})
+	di.Provide(func() *testKafka { return new(testKafka) })
+
+	err := di.Build(context.Background())
+	if err == nil || !strings.Contains(fmt.Sprint(err), "db is down") {
+		t.Fatalf("the error of the failed provider should be returned, err=%v", err)
+	}
+}
+
+func TestBuildStructOutputType(t *testing.T) {
+	for i := 0; i < 10; i++ {
+		di := New(WithSilentLog())
+		di.Provide(func() testPair { return testPair{DB: new(testDB), Cache: new(testCache)} })
+
+		// the last node is the pair provider, the container itself is provided first
+		nodes := di.buildProviderDAG()
+		if typ := nodes[len(nodes)-1].outTyp; typ.String() != "*dixinternal.testCache" {
+			t.Fatalf("the struct provider should be named by its first output type, type=%s", typ)
+		}
+	}
+}
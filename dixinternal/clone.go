@@ -0,0 +1,110 @@
+package dixinternal
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+	"sync"
+)
+
+// Clone returns a container with the options and provider registrations of x and no instantiated objects,
+// every provider is evaluated again in the clone. Registrations on either side do not affect the other one.
+func (x *Dix) Clone() *Dix {
+	return x.copyDix(false)
+}
+
+// Fork returns a container which shares the objects x has already built and isolates everything after,
+// the providers evaluated in the fork, the new registrations and the overrides are not seen by x.
+// Forks of one built baseline are cheap, e.g. one per parallel test. Close of a fork only closes the objects it built.
+func (x *Dix) Fork() *Dix {
+	return x.copyDix(true)
+}
+
+func (x *Dix) copyDix(withObjects bool) *Dix {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	c := &Dix{
+		option:      x.option,
+		providers:   make(map[outputType][]*providerFn, len(x.providers)),
+		objects:     make(map[outputType]map[group][]value),
+		initializer: map[reflect.Value]bool{},
+		initLocks:   map[reflect.Value]*sync.Mutex{},
+		initStats:   map[reflect.Value]*providerStat{},
+		observers:   x.observers,
+		log:         x.log,
+		providerSeq: x.providerSeq,
+		depGraph:    make(map[reflect.Type]map[reflect.Type]bool, len(x.depGraph)),
+		cyclePath:   x.cyclePath,
+		usage:       make(map[outputType]*typeUsage),
+		origins:     make(map[outputType]map[group][]int),
+		fakes:       make(map[outputType]value),
+		disabled:    map[reflect.Value]bool{},
+	}
+
+	// the provider funcs do not change after Provide, the slices do
+	for typ, providers := range x.providers {
+		c.providers[typ] = slices.Clone(providers)
+	}
+
+	for typ, deps := range x.depGraph {
+		c.depGraph[typ] = maps.Clone(deps)
+	}
+
+	x.plans.Range(func(typ, plan any) bool {
+		c.plans.Store(typ, plan)
+		return true
+	})
+
+	// the copy provides itself instead of x, it is the first provider like in newDix
+	selfTyp := reflect.TypeOf(x)
+	i := slices.IndexFunc(c.providers[selfTyp], (*providerFn).isSelf)
+	self := c.providers[selfTyp][i]
+	c.providers[selfTyp][i] = &providerFn{
+		fn:        reflect.ValueOf(func() *Dix { return c }),
+		inputList: self.inputList,
+		output:    self.output,
+		hasError:  self.hasError,
+		seq:       self.seq,
+	}
+
+	if !withObjects {
+		return c
+	}
+
+	for typ, objects := range x.objects {
+		if typ == selfTyp {
+			continue
+		}
+
+		c.objects[typ] = make(map[group][]value, len(objects))
+		c.origins[typ] = make(map[group][]int, len(objects))
+		for ns, values := range objects {
+			c.objects[typ][ns] = slices.Clone(values)
+			c.origins[typ][ns] = slices.Clone(x.origins[typ][ns])
+		}
+	}
+
+	for fn, ok := range x.initializer {
+		if fn != self.fn {
+			c.initializer[fn] = ok
+		}
+	}
+
+	maps.Copy(c.disabled, x.disabled)
+	for fn, stat := range x.initStats {
+		if fn != self.fn {
+			stat := *stat
+			c.initStats[fn] = &stat
+		}
+	}
+
+	maps.Copy(c.fakes, x.fakes)
+	for typ, usage := range x.usage {
+		c.usage[typ] = usage.clone()
+	}
+	return c
+}
+
+// isSelf reports whether n provides the container itself, newDix registers it first and copyDix replaces it
+func (n *providerFn) isSelf() bool { return n.seq == 0 }
@@ -0,0 +1,87 @@
+package dixinternal
+
+import (
+	"context"
+	"testing"
+)
+
+func newCloneBaseline(t *testing.T) *Dix {
+	t.Helper()
+
+	di := New(WithSilentLog())
+	di.Provide(func() *testRedis { return &testRedis{Addr: "baseline"} })
+	di.Provide(func(*testRedis) *testDB { return new(testDB) })
+	if err := di.Build(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return di
+}
+
+func TestForkIsolation(t *testing.T) {
+	di := newCloneBaseline(t)
+	var db *testDB
+	di.Inject(func(d *testDB) { db = d })
+
+	fork := di.Fork()
+	fork.Provide(func() *testKafka { return new(testKafka) })
+	restore := fork.Override(func() *testRedis { return &testRedis{Addr: "fork"} })
+	defer restore()
+
+	fork.Inject(func(r *testRedis, d *testDB, _ *testKafka) {
+		if r.Addr != "fork" {
+			t.Fatalf("the fork should see its override, addr=%s", r.Addr)
+		}
+		if d != db {
+			t.Fatal("the fork should share the objects the baseline has built")
+		}
+	})
+
+	di.Inject(func(r *testRedis) {
+		if r.Addr != "baseline" {
+			t.Fatalf("the override of the fork should not leak into the baseline, addr=%s", r.Addr)
+		}
+	})
+	if err := di.TryInject(func(*testKafka) {}); err == nil {
+		t.Fatal("the providers of the fork should not leak into the baseline")
+	}
+
+	// the fork provides itself, not the baseline
+	fork.Inject(func(self *Dix) {
+		if self != fork {
+			t.Fatal("the fork should inject itself")
+		}
+	})
+
+	if r := fork.Unused(); len(r.Providers) != 0 || len(r.Objects) != 0 {
+		t.Fatalf("the fork should have nothing unused, report=%s", r)
+	}
+}
+
+func TestCloneIsolation(t *testing.T) {
+	di := newCloneBaseline(t)
+	var redis *testRedis
+	di.Inject(func(r *testRedis) { redis = r })
+
+	clone := di.Clone()
+	clone.Provide(func() *testKafka { return new(testKafka) })
+	clone.Inject(func(r *testRedis, _ *testKafka) {
+		if r == redis || r.Addr != "baseline" {
+			t.Fatal("the clone should evaluate the providers again")
+		}
+	})
+
+	if err := di.TryInject(func(*testKafka) {}); err == nil {
+		t.Fatal("the providers of the clone should not leak into the baseline")
+	}
+
+	// the baseline registrations after the clone are not seen by the clone
+	di.Provide(func() *testCache { return new(testCache) })
+	if err := clone.TryInject(func(*testCache) {}); err == nil {
+		t.Fatal("the providers of the baseline should not leak into the clone")
+	}
+
+	r := clone.Unused()
+	if len(r.Providers) != 1 || r.Providers[0].Outputs[0] != "*dixinternal.testDB" {
+		t.Fatalf("only the provider the clone did not evaluate should be unused, report=%s", r)
+	}
+}
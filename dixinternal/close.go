@@ -0,0 +1,45 @@
+package dixinternal
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+
+	"github.com/pubgo/funk/errors"
+)
+
+// addCloser records the object for Close when it implements io.Closer, x.mu must be held
+func (x *Dix) addCloser(v value) {
+	if !v.IsValid() || !v.CanInterface() {
+		return
+	}
+
+	c, ok := v.Interface().(io.Closer)
+	if !ok || !reflect.TypeOf(c).Comparable() {
+		return
+	}
+
+	if c == io.Closer(x) || slices.Contains(x.closers, c) {
+		return
+	}
+	x.closers = append(x.closers, c)
+}
+
+// Close closes the instantiated objects which implement io.Closer in the reverse order of their instantiation,
+// an object is closed once even if it is provided under several types or namespaces
+func (x *Dix) Close() error {
+	x.mu.Lock()
+	closers := x.closers
+	x.closers = nil
+	x.mu.Unlock()
+
+	var errs []error
+	for _, c := range slices.Backward(closers) {
+		if err := c.Close(); err != nil {
+			x.log.Error("failed to close object", "type", fmt.Sprintf("%T", c), "error", err.Error())
+			errs = append(errs, errors.Wrapf(err, "failed to close %T", c))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,93 @@
+package dixinternal
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type testRedis struct {
+	Addr string
+}
+
+type testHandler struct {
+	Redis *testRedis
+	All   map[string]*testRedis
+}
+
+// run with go test -race
+func TestConcurrentInject(t *testing.T) {
+	var count atomic.Int32
+	di := New(WithSilentLog())
+	di.Provide(func() *testRedis {
+		count.Add(1)
+		time.Sleep(10 * time.Millisecond)
+		return &testRedis{Addr: "localhost:6379"}
+	})
+
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			h := new(testHandler)
+			if err := di.TryInject(h); err != nil {
+				t.Error(err)
+				return
+			}
+
+			if h.Redis == nil || h.All["default"] == nil {
+				t.Error("the handler is not injected")
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+			di.Provide(func() map[string]*testRedis { return nil })
+			_ = di.Graph()
+			_ = di.Stats()
+		}()
+	}
+	wg.Wait()
+
+	if count.Load() != 1 {
+		t.Fatalf("the provider should be evaluated once, count=%d", count.Load())
+	}
+}
+
+func TestConcurrentInjectSameProvider(t *testing.T) {
+	var count atomic.Int32
+	di := New(WithSilentLog())
+	di.Provide(func() *testRedis {
+		count.Add(1)
+		return &testRedis{}
+	})
+
+	start := make(chan struct{})
+	values := make([]*testRedis, 32)
+
+	var wg sync.WaitGroup
+	for i := range values {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			if err := di.TryInject(func(r *testRedis) { values[i] = r }); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	if count.Load() != 1 {
+		t.Fatalf("the provider should be evaluated once, count=%d", count.Load())
+	}
+
+	for _, v := range values {
+		if v != values[0] {
+			t.Fatal("every goroutine should get the same object")
+		}
+	}
+}
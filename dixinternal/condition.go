@@ -0,0 +1,104 @@
+package dixinternal
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+
+	"github.com/pubgo/funk/assert"
+	"github.com/pubgo/funk/errors"
+	"github.com/pubgo/funk/v2/result"
+)
+
+// Condition enables a provider at evaluation time, a disabled provider is evaluated as providing no value.
+// All conditions of a provider must hold, see If and Profile.
+type Condition struct {
+	fn       reflect.Value
+	profiles []string
+}
+
+func (c Condition) String() string {
+	if c.fn.IsValid() {
+		return fmt.Sprintf("if %s", c.fn.Type())
+	}
+	return fmt.Sprintf("profile %v", c.profiles)
+}
+
+// If enables the provider when fn returns true, fn is a func returning bool whose parameters are injected like
+// the parameters of an Inject func, e.g. func(cfg *Config) bool. The inputs of fn are dependencies of the provider,
+// an input depending on the provider is a dependency cycle like one of its parameters.
+func If(fn any) Condition {
+	fnVal := reflect.ValueOf(fn)
+	assert.If(fnVal.Kind() != reflect.Func || fnVal.IsNil(), "the condition should be a func")
+	assert.If(fnVal.Type().NumOut() != 1 || fnVal.Type().Out(0).Kind() != reflect.Bool,
+		"the condition func should return bool, type=%s", fnVal.Type())
+	return Condition{fn: fnVal}
+}
+
+// Profile enables the provider when one of the profiles is active, see WithProfiles
+func Profile(profiles ...string) Condition {
+	assert.If(len(profiles) == 0, "the profile condition should have profiles")
+	return Condition{profiles: profiles}
+}
+
+// WithProfiles activates the profiles of the Profile conditions
+func WithProfiles(profiles ...string) Option {
+	return func(opts *Options) {
+		opts.Profiles = append(opts.Profiles, profiles...)
+	}
+}
+
+// conditionInputs returns the inputs the If conditions are injected with,
+// they are dependencies of the provider like its parameters
+func (x *Dix) conditionInputs(conds []Condition) []*providerInputType {
+	var input []*providerInputType
+	for _, c := range conds {
+		if !c.fn.IsValid() {
+			continue
+		}
+
+		for i := 0; i < c.fn.Type().NumIn(); i++ {
+			input = append(input, x.getProvideInput(c.fn.Type().In(i))...)
+		}
+	}
+	return input
+}
+
+// providerInputs returns the parameters and the condition inputs of the provider
+func (x *Dix) providerInputs(n *providerFn) []*providerInputType {
+	return append(slices.Clone(n.inputList), x.conditionInputs(n.conds)...)
+}
+
+// enabled evaluates the conditions of the provider in the scope of its module
+func (x *Dix) enabled(n *providerFn, opt Options) (r result.Result[bool]) {
+	for _, c := range n.conds {
+		if len(c.profiles) > 0 {
+			if !slices.ContainsFunc(c.profiles, func(p string) bool { return slices.Contains(x.option.Profiles, p) }) {
+				return r.WithValue(false)
+			}
+			continue
+		}
+
+		// the condition is injected through a func of the same inputs which keeps the result
+		var enabled bool
+		fnTyp := c.fn.Type()
+		ins := make([]reflect.Type, fnTyp.NumIn())
+		for i := range ins {
+			ins[i] = fnTyp.In(i)
+		}
+
+		wrapper := reflect.MakeFunc(reflect.FuncOf(ins, nil, false), func(args []reflect.Value) []reflect.Value {
+			enabled = c.fn.Call(args)[0].Bool()
+			return nil
+		})
+
+		if err := x.injectFunc(wrapper, opt).GetErr(); err != nil {
+			return r.WithErr(errors.Wrapf(err, "failed to evaluate the provider condition, condition=%s", c))
+		}
+
+		if !enabled {
+			return r.WithValue(false)
+		}
+	}
+	return r.WithValue(true)
+}
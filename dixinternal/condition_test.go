@@ -0,0 +1,59 @@
+package dixinternal
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConditionCycle(t *testing.T) {
+	di := New(WithSilentLog())
+	di.Provide(func() *testRedis { return new(testRedis) }, If(func(*testRedis) bool { return true }))
+
+	done := make(chan error, 1)
+	go func() { done <- di.TryInject(func(*testRedis) {}) }()
+
+	select {
+	case err := <-done:
+		if err == nil || !strings.Contains(err.Error(), "circular dependency") {
+			t.Fatalf("the condition on its own provider should be a cycle, err=%v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the injection hangs on the condition of the provider")
+	}
+
+	if cycles := di.Cycles(); len(cycles) != 1 || cycles[0].Types[0] != "*dixinternal.testRedis" {
+		t.Fatalf("the condition edge should be reported, cycles=%v", cycles)
+	}
+}
+
+func TestConditionRejectCycle(t *testing.T) {
+	di := New(WithSilentLog(), WithRejectCycle())
+	di.Provide(func(*testCache) *testDB { return new(testDB) })
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("the condition should close the cycle")
+		}
+	}()
+	di.Provide(func() *testCache { return new(testCache) }, If(func(*testDB) bool { return true }))
+}
+
+func TestConditionBuildDAG(t *testing.T) {
+	di := New(WithSilentLog())
+	di.Provide(func() *testDB { return new(testDB) })
+	di.Provide(func() *testCache { return new(testCache) }, If(func(*testDB) bool { return true }))
+
+	for _, node := range di.buildProviderDAG() {
+		if node.outTyp != reflect.TypeOf(new(testCache)) {
+			continue
+		}
+
+		if len(node.deps) != 1 || node.deps[0].outTyp != reflect.TypeOf(new(testDB)) {
+			t.Fatalf("the cache should wait on the db of its condition, deps=%d", len(node.deps))
+		}
+		return
+	}
+	t.Fatal("the cache provider should be in the DAG")
+}
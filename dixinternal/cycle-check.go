@@ -1,18 +1,177 @@
 package dixinternal
 
 import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
 	"strings"
+
+	"github.com/pubgo/funk/stack"
 )
 
-// isCycle Check whether type circular dependency
+// isCycle Check whether type circular dependency, the result is maintained incrementally by provide
 func (x *Dix) isCycle() (string, bool) {
-	depGraph := buildDependencyGraph(x.providers)
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	return x.cyclePath, x.cyclePath != ""
+}
+
+// providerEdges returns the dependency edges a provider adds, output type -> input types
+func providerEdges(log Logger, out reflect.Type, in []*providerInputType) map[reflect.Type]map[reflect.Type]bool {
+	edges := make(map[reflect.Type]map[reflect.Type]bool)
+	for _, outTyp := range providerOutputTypes(out) {
+		if edges[outTyp] == nil {
+			edges[outTyp] = make(map[reflect.Type]bool)
+		}
 
-	cyclePath := detectCycle(depGraph)
-	if len(cyclePath) == 0 {
-		return "", false
+		for _, input := range in {
+			for _, provider := range getProvideAllInputs(log, input.typ) {
+				edges[outTyp][provider.typ] = true
+			}
+		}
 	}
+	return edges
+}
 
+// providerOutputTypes returns the types handleProvide registers the provider under
+func providerOutputTypes(out reflect.Type) []reflect.Type {
+	switch out.Kind() {
+	case reflect.Slice:
+		return []reflect.Type{out.Elem()}
+	case reflect.Map:
+		if out.Elem().Kind() == reflect.Slice {
+			return []reflect.Type{out.Elem().Elem()}
+		}
+		return []reflect.Type{out.Elem()}
+	case reflect.Ptr, reflect.Interface, reflect.Func:
+		return []reflect.Type{out}
+	case reflect.Struct:
+		var types []reflect.Type
+		for i := 0; i < out.NumField(); i++ {
+			if !out.Field(i).IsExported() || !isSupportedType(out.Field(i).Type) {
+				continue
+			}
+			types = append(types, providerOutputTypes(out.Field(i).Type)...)
+		}
+		return types
+	default:
+		return nil
+	}
+}
+
+// checkCycle returns the cycle path the edges would create, only the part of the graph reachable from the new edges is visited
+func (x *Dix) checkCycle(edges map[reflect.Type]map[reflect.Type]bool) string {
+	neighbors := func(t reflect.Type) []reflect.Type {
+		var deps []reflect.Type
+		for dep := range x.depGraph[t] {
+			deps = append(deps, dep)
+		}
+		for dep := range edges[t] {
+			if !x.depGraph[t][dep] {
+				deps = append(deps, dep)
+			}
+		}
+		sort.Slice(deps, func(i, j int) bool { return deps[i].String() < deps[j].String() })
+		return deps
+	}
+
+	var outTypes []reflect.Type
+	for out := range edges {
+		outTypes = append(outTypes, out)
+	}
+	sort.Slice(outTypes, func(i, j int) bool { return outTypes[i].String() < outTypes[j].String() })
+
+	for _, out := range outTypes {
+		visited := make(map[reflect.Type]bool)
+
+		var dfs func(t reflect.Type, path []reflect.Type) []reflect.Type
+		dfs = func(t reflect.Type, path []reflect.Type) []reflect.Type {
+			if t == out {
+				return path
+			}
+
+			if visited[t] {
+				return nil
+			}
+			visited[t] = true
+
+			for _, dep := range neighbors(t) {
+				if cycle := dfs(dep, append(path, dep)); cycle != nil {
+					return cycle
+				}
+			}
+			return nil
+		}
+
+		for _, dep := range neighbors(out) {
+			if !edges[out][dep] {
+				continue
+			}
+
+			if cycle := dfs(dep, []reflect.Type{out, dep}); cycle != nil {
+				return cyclePathString(cycle)
+			}
+		}
+	}
+	return ""
+}
+
+func (x *Dix) addEdges(edges map[reflect.Type]map[reflect.Type]bool) {
+	for out, deps := range edges {
+		if x.depGraph[out] == nil {
+			x.depGraph[out] = make(map[reflect.Type]bool)
+		}
+
+		for dep := range deps {
+			x.depGraph[out][dep] = true
+		}
+	}
+}
+
+// rebuildDepGraph replays the edges of the registered providers in registration order,
+// it drops the edges and the cycle of the providers which were removed
+func (x *Dix) rebuildDepGraph() {
+	edgesBySeq := make(map[int]map[reflect.Type]map[reflect.Type]bool)
+	for outTyp, providers := range x.providers {
+		for _, n := range providers {
+			edges := edgesBySeq[n.seq]
+			if edges == nil {
+				edges = make(map[reflect.Type]map[reflect.Type]bool)
+				edgesBySeq[n.seq] = edges
+			}
+
+			if edges[outTyp] == nil {
+				edges[outTyp] = make(map[reflect.Type]bool)
+			}
+
+			for _, input := range x.providerInputs(n) {
+				for _, provider := range getProvideAllInputs(x.log, input.typ) {
+					edges[outTyp][provider.typ] = true
+				}
+			}
+		}
+	}
+
+	var seqs []int
+	for seq := range edgesBySeq {
+		seqs = append(seqs, seq)
+	}
+	sort.Ints(seqs)
+
+	x.depGraph = make(map[reflect.Type]map[reflect.Type]bool)
+	x.cyclePath = ""
+	for _, seq := range seqs {
+		edges := edgesBySeq[seq]
+		if cyclePath := x.checkCycle(edges); cyclePath != "" && x.cyclePath == "" {
+			x.cyclePath = cyclePath
+		}
+		x.addEdges(edges)
+	}
+}
+
+func cyclePathString(cyclePath []reflect.Type) string {
 	var pathStr strings.Builder
 	for i, t := range cyclePath {
 		if i > 0 {
@@ -20,5 +179,241 @@ func (x *Dix) isCycle() (string, bool) {
 		}
 		pathStr.WriteString(t.String())
 	}
-	return pathStr.String(), true
+	return pathStr.String()
+}
+
+// CycleEdge is a dependency edge of a cycle, From depends on To through the providers of From
+type CycleEdge struct {
+	From      string   `json:"from"`
+	To        string   `json:"to"`
+	Providers []string `json:"providers"`
+	Via       []string `json:"via,omitempty"`
+}
+
+// Cycle is a strongly connected component of the type dependency graph
+type Cycle struct {
+	// Types are the sorted members of the component
+	Types []string `json:"types"`
+
+	// Path is the canonical cycle, it starts and ends at the first of Types
+	Path []CycleEdge `json:"path"`
+}
+
+func (c Cycle) String() string {
+	var pathStr strings.Builder
+	for i, edge := range c.Path {
+		if i == 0 {
+			pathStr.WriteString(edge.From)
+		}
+		fmt.Fprintf(&pathStr, " -[%s]-> %s", strings.Join(edge.Providers, ","), edge.To)
+	}
+	return pathStr.String()
+}
+
+type cycleEdgeInfo struct {
+	providers []string
+	via       []string
+}
+
+type cycleGraph map[reflect.Type]map[reflect.Type]*cycleEdgeInfo
+
+// buildCycleGraph builds the type dependency graph, every edge records the providers and parameter object paths it comes from
+func (x *Dix) buildCycleGraph() cycleGraph {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	var nodes []*providerFn
+	outTypes := make(map[*providerFn]reflect.Type)
+	for outTyp, providers := range x.providers {
+		for _, n := range providers {
+			nodes = append(nodes, n)
+			outTypes[n] = outTyp
+		}
+	}
+	sort.SliceStable(nodes, func(i, j int) bool { return nodes[i].seq < nodes[j].seq })
+
+	graph := make(cycleGraph)
+	for _, n := range nodes {
+		outTyp := outTypes[n]
+		if graph[outTyp] == nil {
+			graph[outTyp] = make(map[reflect.Type]*cycleEdgeInfo)
+		}
+
+		fnName := stack.CallerWithFunc(n.fn).String()
+		for _, input := range x.providerInputs(n) {
+			walkProvideInputs(x.log, input.typ, "", func(in *providerInputType, via string) {
+				edge := graph[outTyp][in.typ]
+				if edge == nil {
+					edge = new(cycleEdgeInfo)
+					graph[outTyp][in.typ] = edge
+				}
+
+				if !slices.Contains(edge.providers, fnName) {
+					edge.providers = append(edge.providers, fnName)
+				}
+
+				if via != "" && !slices.Contains(edge.via, via) {
+					edge.via = append(edge.via, via)
+				}
+			})
+		}
+	}
+	return graph
+}
+
+func (g cycleGraph) neighbors(t reflect.Type) []reflect.Type {
+	var deps []reflect.Type
+	for dep := range g[t] {
+		deps = append(deps, dep)
+	}
+	sortTypes(deps)
+	return deps
+}
+
+// findSCCs returns the strongly connected components of the graph with Tarjan's algorithm,
+// only components with more than one type or a self loop are cycles
+func findSCCs(g cycleGraph) [][]reflect.Type {
+	var types []reflect.Type
+	for t := range g {
+		types = append(types, t)
+	}
+	sortTypes(types)
+
+	var (
+		index   = 0
+		indexes = make(map[reflect.Type]int)
+		lowLink = make(map[reflect.Type]int)
+		onStack = make(map[reflect.Type]bool)
+		stk     []reflect.Type
+		sccs    [][]reflect.Type
+	)
+
+	var strongConnect func(t reflect.Type)
+	strongConnect = func(t reflect.Type) {
+		indexes[t] = index
+		lowLink[t] = index
+		index++
+		stk = append(stk, t)
+		onStack[t] = true
+
+		for _, dep := range g.neighbors(t) {
+			if _, ok := indexes[dep]; !ok {
+				strongConnect(dep)
+				lowLink[t] = min(lowLink[t], lowLink[dep])
+			} else if onStack[dep] {
+				lowLink[t] = min(lowLink[t], indexes[dep])
+			}
+		}
+
+		if lowLink[t] != indexes[t] {
+			return
+		}
+
+		var scc []reflect.Type
+		for {
+			top := stk[len(stk)-1]
+			stk = stk[:len(stk)-1]
+			onStack[top] = false
+			scc = append(scc, top)
+			if top == t {
+				break
+			}
+		}
+
+		if len(scc) > 1 || g[t][t] != nil {
+			sortTypes(scc)
+			sccs = append(sccs, scc)
+		}
+	}
+
+	for _, t := range types {
+		if _, ok := indexes[t]; !ok {
+			strongConnect(t)
+		}
+	}
+
+	sort.Slice(sccs, func(i, j int) bool { return sccs[i][0].String() < sccs[j][0].String() })
+	return sccs
+}
+
+// canonicalCycle returns the shortest cycle from the first type of the component back to itself
+func canonicalCycle(g cycleGraph, scc []reflect.Type) []reflect.Type {
+	start := scc[0]
+	members := make(map[reflect.Type]bool)
+	for _, t := range scc {
+		members[t] = true
+	}
+
+	parents := make(map[reflect.Type]reflect.Type)
+	queue := []reflect.Type{start}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+
+		for _, dep := range g.neighbors(cur) {
+			if !members[dep] {
+				continue
+			}
+
+			if dep == start {
+				path := []reflect.Type{start}
+				for t := cur; t != start; t = parents[t] {
+					path = append(path, t)
+				}
+				slices.Reverse(path[1:])
+				return append(path, start)
+			}
+
+			if _, ok := parents[dep]; ok {
+				continue
+			}
+			parents[dep] = cur
+			queue = append(queue, dep)
+		}
+	}
+	return nil
+}
+
+// cycles returns every dependency cycle of the container in a stable order
+func (x *Dix) cycles() []Cycle {
+	g := x.buildCycleGraph()
+
+	var cycles []Cycle
+	for _, scc := range findSCCs(g) {
+		var cycle Cycle
+		for _, t := range scc {
+			cycle.Types = append(cycle.Types, t.String())
+		}
+
+		path := canonicalCycle(g, scc)
+		for i := 0; i+1 < len(path); i++ {
+			edge := g[path[i]][path[i+1]]
+			cycle.Path = append(cycle.Path, CycleEdge{
+				From:      path[i].String(),
+				To:        path[i+1].String(),
+				Providers: edge.providers,
+				Via:       edge.via,
+			})
+		}
+		cycles = append(cycles, cycle)
+	}
+	return cycles
+}
+
+// cycleReport joins all cycles into one line
+func (x *Dix) cycleReport() string {
+	var paths []string
+	for _, cycle := range x.cycles() {
+		paths = append(paths, cycle.String())
+	}
+	return strings.Join(paths, "; ")
+}
+
+func sortTypes(types []reflect.Type) {
+	sort.Slice(types, func(i, j int) bool {
+		if types[i].String() != types[j].String() {
+			return types[i].String() < types[j].String()
+		}
+		return types[i].PkgPath() < types[j].PkgPath()
+	})
 }
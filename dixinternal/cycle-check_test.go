@@ -0,0 +1,136 @@
+package dixinternal
+
+import (
+	"maps"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRejectCycle(t *testing.T) {
+	di := New(WithSilentLog(), WithRejectCycle())
+	di.Provide(func(*testCache) *testDB { return new(testDB) })
+	di.Provide(func(*testKafka) *testCache { return new(testCache) })
+
+	graph := make(map[reflect.Type]map[reflect.Type]bool)
+	for typ, deps := range di.depGraph {
+		graph[typ] = maps.Clone(deps)
+	}
+	providers := len(di.providers[reflect.TypeOf(new(testKafka))])
+
+	err := func() (err error) {
+		defer func() { err, _ = recover().(error) }()
+		di.Provide(func(*testDB) *testKafka { return new(testKafka) })
+		return nil
+	}()
+
+	const path = "*dixinternal.testKafka -> *dixinternal.testDB -> *dixinternal.testCache -> *dixinternal.testKafka"
+	if err == nil || !strings.Contains(err.Error(), path) {
+		t.Fatalf("the provider closing the cycle should be rejected with the path, err=%v", err)
+	}
+
+	// the rejected provider leaves no trace
+	if !reflect.DeepEqual(di.depGraph, graph) || len(di.providers[reflect.TypeOf(new(testKafka))]) != providers {
+		t.Fatal("the graph should be unchanged after the rejection")
+	}
+
+	if path, ok := di.isCycle(); ok {
+		t.Fatalf("there is no cycle, path=%s", path)
+	}
+
+	di.Provide(func() *testKafka { return new(testKafka) })
+	di.Inject(func(*testDB) {})
+}
+
+func TestIncrementalCycle(t *testing.T) {
+	di := New(WithSilentLog())
+	di.Provide(func(*testCache) *testDB { return new(testDB) })
+	if _, ok := di.isCycle(); ok {
+		t.Fatal("there is no cycle yet")
+	}
+
+	// the first cycle found is kept, a later one does not replace it
+	di.Provide(func(*testDB) *testCache { return new(testCache) })
+	di.Provide(func(*testKafka) *testKafka { return new(testKafka) })
+
+	path, ok := di.isCycle()
+	if !ok || path != "*dixinternal.testCache -> *dixinternal.testDB -> *dixinternal.testCache" {
+		t.Fatalf("path=%s", path)
+	}
+
+	err := di.TryInject(func(*testDB) {})
+	if err == nil || !strings.Contains(err.Error(), "circular dependency") {
+		t.Fatalf("the injection should report the cycle, err=%v", err)
+	}
+}
+
+type cycleA struct{}
+
+type cycleB struct{}
+
+type cycleParam struct{}
+
+func (p *cycleParam) DixInjectB(*cycleB) {}
+
+func cyclePaths(cycles []Cycle) []string {
+	var paths []string
+	for _, cycle := range cycles {
+		var path []string
+		for i, edge := range cycle.Path {
+			if i == 0 {
+				path = append(path, edge.From)
+			}
+			path = append(path, edge.To)
+		}
+		paths = append(paths, strings.Join(path, " -> "))
+	}
+	return paths
+}
+
+func TestCycles(t *testing.T) {
+	providers := []any{
+		func(*testCache) *testDB { return new(testDB) },
+		func(*testDB) *testKafka { return new(testKafka) },
+		func(*testKafka) *testCache { return new(testCache) },
+		func(*testRedis) *testRedis { return new(testRedis) },
+		func(cycleParam) *cycleA { return new(cycleA) },
+		func(*cycleA) *cycleB { return new(cycleB) },
+	}
+
+	di := New(WithSilentLog())
+	for _, p := range providers {
+		di.Provide(p)
+	}
+
+	cycles := di.Cycles()
+	want := []string{
+		"*dixinternal.cycleA -> *dixinternal.cycleB -> *dixinternal.cycleA",
+		"*dixinternal.testCache -> *dixinternal.testKafka -> *dixinternal.testDB -> *dixinternal.testCache",
+		"*dixinternal.testRedis -> *dixinternal.testRedis",
+	}
+	if got := cyclePaths(cycles); !reflect.DeepEqual(got, want) {
+		t.Fatalf("cycles=%q, want %q", got, want)
+	}
+
+	if got := cycles[1].Types; !reflect.DeepEqual(got, []string{"*dixinternal.testCache", "*dixinternal.testDB", "*dixinternal.testKafka"}) {
+		t.Fatalf("the types should be sorted, types=%q", got)
+	}
+
+	// the method of the parameter object is the edge of the cycle
+	if via := cycles[0].Path[0].Via; !reflect.DeepEqual(via, []string{"dixinternal.cycleParam.DixInjectB"}) {
+		t.Fatalf("the edge should come through the method, via=%q", via)
+	}
+
+	if !reflect.DeepEqual(di.Cycles(), cycles) {
+		t.Fatal("cycles should be stable")
+	}
+
+	// the registration order does not change the report
+	reversed := New(WithSilentLog())
+	for i := len(providers) - 1; i >= 0; i-- {
+		reversed.Provide(providers[i])
+	}
+	if got := cyclePaths(reversed.Cycles()); !reflect.DeepEqual(got, want) {
+		t.Fatalf("cycles=%q, want %q", got, want)
+	}
+}
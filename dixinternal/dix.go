@@ -2,9 +2,10 @@ package dixinternal
 
 import (
 	"fmt"
-	"path/filepath"
+	"io"
 	"reflect"
-	"strings"
+	"slices"
+	"sync"
 	"time"
 
 	"github.com/kr/pretty"
@@ -34,116 +35,232 @@ func newDix(opts ...Option) *Dix {
 		providers:   make(map[outputType][]*providerFn),
 		objects:     make(map[outputType]map[group][]value),
 		initializer: map[reflect.Value]bool{},
+		initLocks:   map[reflect.Value]*sync.Mutex{},
+		initStats:   map[reflect.Value]*providerStat{},
+		depGraph:    make(map[reflect.Type]map[reflect.Type]bool),
+		log:         newLogger(option),
+		usage:       make(map[outputType]*typeUsage),
+		origins:     make(map[outputType]map[group][]int),
+		fakes:       make(map[outputType]value),
+		disabled:    map[reflect.Value]bool{},
 	}
+	c.observers = append([]Observer{logObserver{log: c.log}}, option.Observers...)
 
 	c.provide(func() *Dix { return c })
+	c.installPlugins(option.Plugins)
+	c.installModules(option.Modules)
 
 	return c
 }
 
+// Dix is safe for concurrent use, mu guards the maps and initLocks make each provider func evaluated once
 type Dix struct {
 	option      Options
+	mu          sync.RWMutex
 	providers   map[outputType][]*providerFn
 	objects     map[outputType]map[group][]value
 	initializer map[reflect.Value]bool
+	initLocks   map[reflect.Value]*sync.Mutex
+	initStats   map[reflect.Value]*providerStat
+	observers   []Observer
+	log         Logger
+	providerSeq int
+
+	// usage has an entry for every type getValue was asked for, it records the injected objects
+	usage map[outputType]*typeUsage
+
+	// origins are the seq of the provider of each object, in the same order as objects
+	origins map[outputType]map[group][]int
+
+	// depGraph is the type dependency graph, cyclePath is the first cycle found in it
+	depGraph  map[reflect.Type]map[reflect.Type]bool
+	cyclePath string
+
+	// plans caches the compiled injection plan of each struct, func and pointer type
+	plans sync.Map
+
+	// closers are the objects Close closes, in instantiation order
+	closers []io.Closer
+
+	// fakes are the fake objects of the interface types without providers, see WithFakes
+	fakes map[outputType]value
+
+	// disabled are the provider funcs whose conditions did not hold
+	disabled map[reflect.Value]bool
 }
 
 func (x *Dix) Option() Options {
 	return x.option
 }
 
-func (x *Dix) getOutputTypeValues(outTyp outputType, opt Options) (r result.Result[map[group][]value]) {
+func (x *Dix) getOutputTypeValues(outTyp outputType, opt Options, isMap, isList bool) (r result.Result[map[group][]value]) {
 	switch outTyp.Kind() {
 	case reflect.Ptr, reflect.Interface, reflect.Func:
 	default:
 		return r.WithErrorf("provider type kind error, the supported type kinds are <ptr,interface,func>, type=%s kind=%s", outTyp, outTyp.Kind())
 	}
 
-	if len(x.providers[outTyp]) == 0 {
-		logger.Warn().
-			Str("type", outTyp.String()).
-			Str("kind", outTyp.Kind().String()).
-			Msg("provider not found, please check whether the provider imports or type error")
-	}
+	x.mu.RLock()
+	nodes, hidden := x.visibleProviders(outTyp, opt.module)
+	x.mu.RUnlock()
 
-	if x.objects[outTyp] == nil {
-		x.objects[outTyp] = make(map[group][]value)
+	if len(nodes) == 0 && !x.fake(outTyp) {
+		x.log.Warn("provider not found, please check whether the provider imports or type error",
+			"type", outTyp.String(),
+			"kind", outTyp.Kind().String())
 	}
 
-	for _, n := range x.providers[outTyp] {
-		if x.initializer[n.fn] {
+	for _, n := range nodes {
+		if x.isInitialized(n.fn) {
 			continue
 		}
 
-		var input []reflect.Value
-		for _, in := range n.inputList {
-			val := x.getValue(in.typ, opt, in.isMap, in.isList, outTyp).UnwrapErr(&r)
-			if r.IsErr() {
-				return
-			}
-
-			input = append(input, val)
+		if x.evalProvider(outTyp, n, opt).CatchErr(&r) {
+			return
 		}
+	}
 
-		var now = time.Now()
-		var fnStack = stack.CallerWithFunc(n.fn)
+	x.mu.RLock()
+	objects, indexes := x.visibleObjects(outTyp, hidden)
+	usage := x.usage[outTyp]
+	x.mu.RUnlock()
 
-		logger.Debug().
-			Str("provider", fnStack.String()).
-			Msgf("start eval provider func %s.%s", filepath.Base(fnStack.Pkg), fnStack.Name)
+	if usage == nil {
+		usage = x.requestType(outTyp)
+	}
+	usage.markConsumed(indexes, isMap, isList)
+	return r.WithValue(objects)
+}
+
+func (x *Dix) isInitialized(fn reflect.Value) bool {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	return x.initializer[fn]
+}
+
+// initLock returns the lock which makes sure the provider func is evaluated once
+func (x *Dix) initLock(fn reflect.Value) *sync.Mutex {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	if x.initLocks[fn] == nil {
+		x.initLocks[fn] = new(sync.Mutex)
+	}
+	return x.initLocks[fn]
+}
 
-		fnCall := n.call(input).UnwrapErr(&r)
+func (x *Dix) evalProvider(outTyp outputType, n *providerFn, opt Options) (r result.Error) {
+	lock := x.initLock(n.fn)
+	lock.Lock()
+	defer lock.Unlock()
+
+	// another goroutine may have finished it while waiting for the lock
+	if x.isInitialized(n.fn) {
+		return
+	}
+
+	// the inputs are resolved in the scope of the module of the provider
+	opt.module = n.module
+
+	enabled := x.enabled(n, opt).UnwrapErr(&r)
+	if r.IsErr() {
+		return
+	}
+
+	if !enabled {
+		x.mu.Lock()
+		x.initializer[n.fn] = true
+		x.disabled[n.fn] = true
+		x.mu.Unlock()
+		x.log.Debug("provider is disabled by its conditions", "provider", stack.CallerWithFunc(n.fn).String())
+		return
+	}
+
+	var input []reflect.Value
+	for _, in := range n.inputList {
+		val := x.getValue(in.typ, opt, in.isMap, in.isList, outTyp).UnwrapErr(&r)
 		if r.IsErr() {
 			return
 		}
 
-		x.initializer[n.fn] = true
-		logger.Debug().
-			Str("cost", time.Since(now).String()).
-			Str("provider", fnStack.String()).
-			Msgf("eval provider ok, func %s.%s", filepath.Base(fnStack.Pkg), fnStack.Name)
-
-		if n.hasError && len(fnCall) > 1 && !fnCall[1].IsNil() {
-			if err, ok := fnCall[1].Interface().(error); ok && err != nil {
-				return r.WithErr(errors.Wrapf(err, "failed to do provider, provider=%s", fnStack))
-			}
+		input = append(input, val)
+	}
+
+	var now = time.Now()
+	var gid = goroutineID()
+	var evt = newProviderEvent(n.fn, n.seq)
+	x.notify(func(o Observer) { o.ProviderStarted(evt) })
+
+	fnCall := n.call(x.log, input).UnwrapErr(&r)
+	cost := time.Since(now)
+	if r.IsErr() {
+		x.recordStat(n.fn, gid, now, cost, r.GetErr())
+		x.notify(func(o Observer) { o.ProviderFinished(evt, r.GetErr(), cost) })
+		return
+	}
+
+	if n.hasError && len(fnCall) > 1 && !fnCall[1].IsNil() {
+		if err, ok := fnCall[1].Interface().(error); ok && err != nil {
+			x.mu.Lock()
+			x.initializer[n.fn] = true
+			x.mu.Unlock()
+			x.recordStat(n.fn, gid, now, cost, err)
+			x.notify(func(o Observer) { o.ProviderFinished(evt, err, cost) })
+			return r.WithErr(errors.Wrapf(err, "failed to do provider, provider=%s", stack.CallerWithFunc(n.fn)))
 		}
+	}
+	defer x.notify(func(o Observer) { o.ProviderFinished(evt, nil, cost) })
 
-		objects := make(map[outputType]map[group][]value)
-		for outT, groupValue := range handleOutput(outTyp, fnCall[0]) {
-			if n.output.isMap {
-				if _, ok := objects[outT]; ok {
-					logger.Info().
-						Str("type", outTyp.String()).
-						Str("key", outT.String()).
-						Msg("type value exists")
-				}
+	output, dropped := handleOutput(outTyp, fnCall[0])
+	if n.plugin != nil {
+		pluginOutput(n.plugin, output, dropped)
+	}
+	objects := make(map[outputType]map[group][]value)
+	for outT, groupValue := range output {
+		if n.output.isMap {
+			if _, ok := objects[outT]; ok {
+				x.log.Info("type value exists",
+					"type", outTyp.String(),
+					"key", outT.String())
 			}
+		}
 
-			if objects[outT] == nil {
-				objects[outT] = make(map[group][]value)
-			}
+		if objects[outT] == nil {
+			objects[outT] = make(map[group][]value)
+		}
 
-			for g, o := range groupValue {
-				objects[outT][g] = append(objects[outT][g], o...)
-			}
+		for g, o := range groupValue {
+			objects[outT][g] = append(objects[outT][g], o...)
 		}
+	}
 
-		for a, b := range objects {
-			if x.objects[a] == nil {
-				x.objects[a] = make(map[group][]value)
-			}
+	x.mu.Lock()
+	defer x.mu.Unlock()
 
-			for c, d := range b {
-				x.objects[a][c] = append(x.objects[a][c], d...)
+	x.initializer[n.fn] = true
+	x.setStat(n.fn, gid, now, cost, nil)
+	x.initStats[n.fn].dropped = dropped
+	for a, b := range objects {
+		if x.objects[a] == nil {
+			x.objects[a] = make(map[group][]value)
+			x.origins[a] = make(map[group][]int)
+		}
+
+		for c, d := range b {
+			x.objects[a][c] = append(x.objects[a][c], d...)
+			for _, v := range d {
+				x.origins[a][c] = append(x.origins[a][c], n.seq)
+				x.addCloser(v)
 			}
 		}
 	}
-
-	return r.WithValue(x.objects[outTyp])
+	return
 }
 
 func (x *Dix) getProviderStack(typ reflect.Type) []string {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
 	var stacks []string
 	for _, n := range x.providers[typ] {
 		stacks = append(stacks, stack.CallerWithFunc(n.fn).String())
@@ -161,7 +278,7 @@ func (x *Dix) getValue(typ reflect.Type, opt Options, isMap, isList bool, parent
 		return r.WithValue(v)
 	}
 
-	valMap := x.getOutputTypeValues(typ, opt).UnwrapErr(&r)
+	valMap := x.getOutputTypeValues(typ, opt, isMap, isList).UnwrapErr(&r)
 	if r.IsErr() {
 		return
 	}
@@ -174,6 +291,9 @@ func (x *Dix) getValue(typ reflect.Type, opt Options, isMap, isList bool, parent
 			)
 		}
 
+		for ns := range valMap {
+			x.notify(func(o Observer) { o.ValueResolved(typ, ns) })
+		}
 		return r.WithValue(makeMap(typ, valMap, isList))
 	case isList:
 		if !opt.AllowValuesNull && len(valMap[defaultKey]) == 0 {
@@ -191,87 +311,65 @@ func (x *Dix) getValue(typ reflect.Type, opt Options, isMap, isList bool, parent
 			})
 		}
 
+		x.notify(func(o Observer) { o.ValueResolved(typ, defaultKey) })
 		return r.WithValue(makeList(typ, valMap[defaultKey]))
 	default:
-		if valList, ok := valMap[defaultKey]; !ok || len(valList) == 0 {
+		val, err := selectValue(valMap[defaultKey])
+		if err != nil {
+			tags := errors.Maps{
+				"type":      typ.String(),
+				"kind":      typ.Kind().String(),
+				"values":    valMap,
+				"parents":   fmt.Sprintf("%q", parents),
+				"options":   opt,
+				"providers": x.getProviderStack(typ),
+			}
+			if val.IsValid() {
+				tags["value"] = val.Interface()
+			}
+
 			return r.WrapErr(&errors.Err{
-				Msg:    "provider value not found",
+				Msg:    err.Error(),
 				Detail: fmt.Sprintf("type=%s kind=%s allValues=%v", typ, typ.Kind(), valMap),
-				Tags: errors.Maps{
-					"type":      typ.String(),
-					"kind":      typ.Kind().String(),
-					"values":    valMap,
-					"parents":   fmt.Sprintf("%q", parents),
-					"options":   opt,
-					"providers": x.getProviderStack(typ),
-				}.Tags(),
+				Tags:   tags.Tags(),
 			})
-		} else {
-			// 最后一个value
-			val := valList[len(valList)-1]
-			if val.IsZero() {
-				return r.WrapErr(&errors.Err{
-					Msg:    "provider value is null",
-					Detail: fmt.Sprintf("type=%s kind=%s value=%v", typ, typ.Kind(), val.Interface()),
-					Tags: errors.Maps{
-						"type":      typ.String(),
-						"kind":      typ.Kind().String(),
-						"value":     val.Interface(),
-						"values":    valMap,
-						"parents":   fmt.Sprintf("%q", parents),
-						"options":   opt,
-						"providers": x.getProviderStack(typ),
-					}.Tags(),
-				})
-			}
-			return r.WithValue(val)
 		}
+
+		x.notify(func(o Observer) { o.ValueResolved(typ, defaultKey) })
+		return r.WithValue(val)
+	}
+}
+
+var (
+	errValueNotFound = errors.New("provider value not found")
+	errValueNull     = errors.New("provider value is null")
+)
+
+// selectValue returns the value a single value injection gets from the values of the default namespace,
+// the last value wins and it must not be null, the null value is returned with errValueNull
+func selectValue(values []value) (value, error) {
+	if len(values) == 0 {
+		return value{}, errValueNotFound
+	}
+
+	// 最后一个value
+	val := values[len(values)-1]
+	if val.IsZero() {
+		return val, errValueNull
 	}
+	return val, nil
 }
 
 func (x *Dix) injectFunc(vp reflect.Value, opt Options) (r result.Error) {
 	defer result.RecoveryErr(&r)
 
-	assert.If(vp.Type().NumOut() > 1, "func output num should <=1")
-	assert.If(vp.Type().NumIn() == 0, "func input num should not be zero")
-
-	var hasErrorReturn bool
-	if vp.Type().NumOut() == 1 {
-		// 如果有一个返回值，必须是 error 类型
-		errorType := vp.Type().Out(0)
-		if !errorType.Implements(reflect.TypeOf((*error)(nil)).Elem()) {
-			return r.WrapErr(&errors.Err{
-				Msg:    "injectable function can only return error type",
-				Detail: fmt.Sprintf("return_type=%s", errorType.String()),
-			})
-		}
-		hasErrorReturn = true
-	}
-
-	var inTypes []*providerInputType
-	for i := 0; i < vp.Type().NumIn(); i++ {
-		switch inTyp := vp.Type().In(i); inTyp.Kind() {
-		case reflect.Interface, reflect.Ptr, reflect.Func, reflect.Struct:
-			inTypes = append(inTypes, &providerInputType{typ: inTyp, isStruct: inTyp.Kind() == reflect.Struct})
-		case reflect.Map:
-			isList := inTyp.Elem().Kind() == reflect.Slice
-			typ := inTyp.Elem()
-			if isList {
-				typ = typ.Elem()
-			}
-			inTypes = append(inTypes, &providerInputType{typ: typ, isMap: true, isList: isList})
-		case reflect.Slice:
-			inTypes = append(inTypes, &providerInputType{typ: inTyp.Elem(), isList: true})
-		default:
-			return r.WrapErr(&errors.Err{
-				Msg:    "incorrect input type",
-				Detail: fmt.Sprintf("inTyp=%s kind=%s", inTyp, inTyp.Kind()),
-			})
-		}
+	plan := loadPlan(x, vp.Type(), compileFuncPlan)
+	if plan.err != nil {
+		return r.WithErr(plan.err)
 	}
 
 	var input []reflect.Value
-	for _, in := range inTypes {
+	for _, in := range plan.inputs {
 		input = append(input, x.getValue(in.typ, opt, in.isMap, in.isList, vp.Type()).UnwrapErr(&r))
 		if r.IsErr() {
 			return
@@ -280,7 +378,7 @@ func (x *Dix) injectFunc(vp reflect.Value, opt Options) (r result.Error) {
 
 	results := vp.Call(input)
 	// 如果函数有 error 返回值，检查并处理
-	if hasErrorReturn && len(results) > 0 && !results[0].IsNil() {
+	if plan.hasErrorReturn && len(results) > 0 && !results[0].IsNil() {
 		errorValue := results[0]
 		if funcErr, ok := errorValue.Interface().(error); ok {
 			return result.ErrOf(errors.Wrap(funcErr, "injected function returned error"))
@@ -290,41 +388,29 @@ func (x *Dix) injectFunc(vp reflect.Value, opt Options) (r result.Error) {
 }
 
 func (x *Dix) injectStruct(vp reflect.Value, opt Options) (r result.Error) {
-	tp := vp.Type()
-	for i := 0; i < tp.NumField(); i++ {
-		field := tp.Field(i)
-		if !vp.Field(i).CanSet() && field.Type.Kind() != reflect.Struct {
+	plan := loadPlan(x, vp.Type(), compileStructPlan)
+	for _, f := range plan.fields {
+		fv := vp.Field(f.index)
+		if !fv.CanSet() && f.kind != fieldStruct {
 			continue
 		}
 
-		switch field.Type.Kind() {
-		case reflect.Struct:
-			x.injectStruct(vp.Field(i), opt)
-		case reflect.Interface, reflect.Ptr, reflect.Func:
-			vp.Field(i).Set(x.getValue(field.Type, opt, false, false, vp.Type()).UnwrapErr(&r))
-			if r.IsErr() {
-				return
-			}
-		case reflect.Map:
-			isList := field.Type.Elem().Kind() == reflect.Slice
-			typ := field.Type.Elem()
-			if isList {
-				typ = typ.Elem()
-			}
-
-			vp.Field(i).Set(x.getValue(typ, opt, true, isList, vp.Type()).UnwrapErr(&r))
-			if r.IsErr() {
+		switch f.kind {
+		case fieldValue:
+			if x.injectValue(f.field, fv, opt).CatchErr(&r) {
 				return
 			}
-		case reflect.Slice:
-			vp.Field(i).Set(x.getValue(field.Type.Elem(), opt, false, true, vp.Type()).UnwrapErr(&r))
+		case fieldStruct:
+			x.injectStruct(fv, opt)
+		case fieldInput:
+			fv.Set(x.getValue(f.input.typ, opt, f.input.isMap, f.input.isList, vp.Type()).UnwrapErr(&r))
 			if r.IsErr() {
 				return
 			}
 		default:
 			return r.WrapErr(&errors.Err{
 				Msg:    "incorrect input type",
-				Detail: fmt.Sprintf("inTyp=%s kind=%s", field.Type, field.Type.Kind()),
+				Detail: fmt.Sprintf("inTyp=%s kind=%s", f.field.Type, f.field.Type.Kind()),
 			})
 		}
 	}
@@ -332,6 +418,12 @@ func (x *Dix) injectStruct(vp reflect.Value, opt Options) (r result.Error) {
 }
 
 func (x *Dix) inject(param interface{}, opts ...Option) (r result.Error) {
+	target := reflect.TypeOf(param)
+	x.notify(func(o Observer) { o.InjectStarted(target) })
+	defer func(now time.Time) {
+		x.notify(func(o Observer) { o.InjectFinished(target, r.GetErr(), time.Since(now)) })
+	}(time.Now())
+
 	defer result.RecoveryErr(&r, func(err error) error {
 		return errors.WrapKV(err, "param", pretty.Sprint(param))
 	})
@@ -366,12 +458,7 @@ func (x *Dix) inject(param interface{}, opts ...Option) (r result.Error) {
 		})
 	}
 
-	for i := 0; i < vp.NumMethod(); i++ {
-		name := vp.Type().Method(i).Name
-		if !strings.HasPrefix(name, InjectMethodPrefix) {
-			continue
-		}
-
+	for _, i := range loadPlan(x, vp.Type(), compileMethodPlan).methods {
 		if x.injectFunc(vp.Method(i), opt).CatchErr(&r) {
 			return
 		}
@@ -391,7 +478,7 @@ func (x *Dix) inject(param interface{}, opts ...Option) (r result.Error) {
 	return x.injectStruct(vp, opt)
 }
 
-func (x *Dix) handleProvide(fnVal reflect.Value, out reflect.Type, in []*providerInputType) (r result.Error) {
+func (x *Dix) handleProvide(fnVal reflect.Value, out reflect.Type, in []*providerInputType, scope providerScope) (r result.Error) {
 	hasError := false
 	if fnVal.Type().NumOut() == 2 {
 		errorType := fnVal.Type().Out(1)
@@ -405,7 +492,16 @@ func (x *Dix) handleProvide(fnVal reflect.Value, out reflect.Type, in []*provide
 		}
 	}
 
-	n := &providerFn{fn: fnVal, inputList: in, hasError: hasError}
+	n := &providerFn{
+		fn:        fnVal,
+		inputList: in,
+		hasError:  hasError,
+		seq:       x.providerSeq,
+		module:    scope.module,
+		private:   scope.private,
+		conds:     scope.conds,
+		plugin:    scope.plugin,
+	}
 	switch outTyp := out; outTyp.Kind() {
 	case reflect.Slice:
 		n.output = &providerOutputType{isList: true, typ: outTyp.Elem()}
@@ -432,13 +528,13 @@ func (x *Dix) handleProvide(fnVal reflect.Value, out reflect.Type, in []*provide
 				continue
 			}
 
-			x.handleProvide(fnVal, typ, in).CatchErr(&r)
+			x.handleProvide(fnVal, typ, in, scope).CatchErr(&r)
 			if r.IsErr() {
 				return
 			}
 		}
 	default:
-		logger.Error().Msgf("incorrect output type, ouTyp=%s kind=%s fnVal=%s", outTyp, outTyp.Kind(), fnVal.String())
+		x.log.Error(fmt.Sprintf("incorrect output type, ouTyp=%s kind=%s fnVal=%s", outTyp, outTyp.Kind(), fnVal.String()))
 	}
 	return
 }
@@ -457,7 +553,7 @@ func (x *Dix) getProvideInput(typ reflect.Type) []*providerInputType {
 	case reflect.Slice:
 		input = append(input, &providerInputType{typ: inTye.Elem(), isList: true})
 	default:
-		logger.Error().Msgf("incorrect input type, inTyp=%s kind=%s", inTye, inTye.Kind())
+		x.log.Error(fmt.Sprintf("incorrect input type, inTyp=%s kind=%s", inTye, inTye.Kind()))
 	}
 	return input
 }
@@ -468,6 +564,11 @@ func (x *Dix) getProvideInput(typ reflect.Type) []*providerInputType {
 // and return values are treated as results that can be injected elsewhere.
 // Provide panics if the constructor is not a function or does not have the required signature.
 func (x *Dix) provide(param interface{}) {
+	x.provideIn(param, providerScope{})
+}
+
+// provideIn registers the provider in the scope, the zero scope is the container outside of modules
+func (x *Dix) provideIn(param interface{}, scope providerScope) (seq int) {
 	defer recovery.Raise(func(err error) error {
 		return errors.WrapKV(err, "param", pretty.Sprint(param))
 	})
@@ -493,7 +594,36 @@ func (x *Dix) provide(param interface{}) {
 		input = append(input, x.getProvideInput(typ.In(i))...)
 	}
 
+	condInput := x.conditionInputs(scope.conds)
+
+	// the observers are notified after the lock is released
+	seq = -1
+	defer func() {
+		if seq >= 0 {
+			evt := newProviderEvent(fnVal, seq)
+			x.notify(func(o Observer) { o.ProviderRegistered(evt) })
+		}
+	}()
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	// a condition injected with the output of the provider would wait on the provider being evaluated
+	edges := providerEdges(x.log, typ.Out(0), append(slices.Clone(input), condInput...))
+	cyclePath := x.checkCycle(edges)
+	if cyclePath != "" && x.option.RejectCycle {
+		assert.Must(errors.New("circular dependency: " + cyclePath))
+	}
+
 	// The return value can only have one
 	// TODO Add the second parameter, support for error
-	x.handleProvide(fnVal, typ.Out(0), input).Must()
+	x.handleProvide(fnVal, typ.Out(0), input, scope).Must()
+	seq = x.providerSeq
+	x.providerSeq++
+
+	x.addEdges(edges)
+	if cyclePath != "" && x.cyclePath == "" {
+		x.cyclePath = cyclePath
+	}
+	return
 }
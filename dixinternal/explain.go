@@ -0,0 +1,261 @@
+package dixinternal
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/pubgo/funk/stack"
+)
+
+// DroppedValue is a nil value handleOutput removed from a provider output
+type DroppedValue struct {
+	Type      string `json:"type"`
+	Namespace string `json:"namespace"`
+	Reason    string `json:"reason"`
+}
+
+// Candidate is a provider registered for the explained type
+type Candidate struct {
+	Func      string `json:"func"`
+	Location  string `json:"location"`
+	Seq       int    `json:"seq"`
+	Evaluated bool   `json:"evaluated"`
+	Disabled  bool   `json:"disabled,omitempty"`
+	Error     string `json:"error,omitempty"`
+
+	// Module is the module which registered the provider, a Private one is hidden from the container
+	Module  string `json:"module,omitempty"`
+	Private bool   `json:"private,omitempty"`
+
+	// Namespaces are the namespace keys the provider contributed values to, sorted
+	Namespaces []string       `json:"namespaces,omitempty"`
+	Dropped    []DroppedValue `json:"dropped,omitempty"`
+}
+
+// ExplainedValue is an object of the explained namespace
+type ExplainedValue struct {
+	Index    int    `json:"index"`
+	Value    string `json:"value"`
+	Provider string `json:"provider"`
+	Seq      int    `json:"seq"`
+
+	// Hidden marks the value of a private provider, the container does not inject it
+	Hidden bool `json:"hidden,omitempty"`
+}
+
+// Explanation tells how a type resolves in a namespace
+type Explanation struct {
+	Type      string `json:"type"`
+	Namespace string `json:"namespace"`
+
+	// Candidates are the providers of the type in registration order
+	Candidates []Candidate `json:"candidates"`
+
+	// Values are the objects of the namespace in order, Winner is the one the container injects,
+	// into a single value for the default namespace or into a map for the others,
+	// Error is why a single value injection fails
+	Values []ExplainedValue `json:"values"`
+	Winner *ExplainedValue  `json:"winner,omitempty"`
+	Error  string           `json:"error,omitempty"`
+
+	AllowValuesNull bool     `json:"allow_values_null"`
+	Notes           []string `json:"notes,omitempty"`
+}
+
+func (e *Explanation) String() string {
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "%s in namespace %q\n", e.Type, e.Namespace)
+
+	fmt.Fprintf(&buf, "candidates:\n")
+	if len(e.Candidates) == 0 {
+		fmt.Fprintf(&buf, "  none\n")
+	}
+	for _, c := range e.Candidates {
+		state := "not evaluated"
+		if c.Evaluated {
+			state = "evaluated"
+		}
+		if c.Disabled {
+			state = "disabled by conditions"
+		}
+		if c.Error != "" {
+			state = "failed: " + c.Error
+		}
+		if c.Private {
+			state += ", private to module " + c.Module
+		}
+
+		fmt.Fprintf(&buf, "  #%d %s (%s) %s", c.Seq, c.Func, c.Location, state)
+		if len(c.Namespaces) > 0 {
+			fmt.Fprintf(&buf, ", namespaces=%s", strings.Join(c.Namespaces, ","))
+		}
+		buf.WriteString("\n")
+
+		for _, d := range c.Dropped {
+			fmt.Fprintf(&buf, "    dropped %s in namespace %q: %s\n", d.Type, d.Namespace, d.Reason)
+		}
+	}
+
+	fmt.Fprintf(&buf, "values:\n")
+	if len(e.Values) == 0 {
+		fmt.Fprintf(&buf, "  none\n")
+	}
+	for _, v := range e.Values {
+		mark := ""
+		switch {
+		case v.Hidden:
+			mark = " (hidden)"
+		case e.Winner != nil && e.Winner.Index == v.Index:
+			mark = " <- wins"
+		}
+		fmt.Fprintf(&buf, "  [%d] %s from #%d %s%s\n", v.Index, v.Value, v.Seq, v.Provider, mark)
+	}
+
+	if e.Error != "" {
+		fmt.Fprintf(&buf, "error: %s\n", e.Error)
+	}
+
+	for _, n := range e.Notes {
+		fmt.Fprintf(&buf, "note: %s\n", n)
+	}
+	return buf.String()
+}
+
+// Explain tells which providers can produce typ, which of them ran, the values they contributed to namespace ns
+// and which value the container injects, selected like getValue does: private values are hidden,
+// the last value wins and a null value fails a single value injection. It does not evaluate any provider.
+// typ is the provided type, e.g. the element type of a list, ns is the default namespace when empty.
+func (x *Dix) Explain(typ reflect.Type, ns string) *Explanation {
+	if ns == "" {
+		ns = defaultKey
+	}
+
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	e := &Explanation{Type: typ.String(), Namespace: ns, AllowValuesNull: x.option.AllowValuesNull}
+
+	// the fake has no provider, its objects come from seq -1
+	funcs := map[int]string{-1: "fake"}
+	bySeq := make(map[int]*Candidate)
+	for _, n := range x.providers[typ] {
+		frame := stack.CallerWithFunc(n.fn)
+		c := &Candidate{
+			Func:      frame.Pkg + "." + frame.Name,
+			Location:  fmt.Sprintf("%s:%d", frame.File, frame.Line),
+			Seq:       n.seq,
+			Evaluated: x.initializer[n.fn],
+			Disabled:  x.disabled[n.fn],
+			Private:   n.private,
+		}
+
+		if n.module != nil {
+			c.Module = n.module.Name()
+		}
+
+		if stat := x.initStats[n.fn]; stat != nil {
+			if stat.err != nil {
+				c.Error = stat.err.Error()
+			}
+
+			for _, d := range stat.dropped {
+				if d.Type == typ.String() {
+					c.Dropped = append(c.Dropped, d)
+				}
+			}
+		}
+
+		funcs[n.seq] = c.Func
+		bySeq[n.seq] = c
+	}
+
+	for g, seqs := range x.origins[typ] {
+		for _, seq := range seqs {
+			if c := bySeq[seq]; c != nil && !slices.Contains(c.Namespaces, g) {
+				c.Namespaces = append(c.Namespaces, g)
+			}
+		}
+	}
+
+	for _, c := range bySeq {
+		sort.Strings(c.Namespaces)
+		e.Candidates = append(e.Candidates, *c)
+	}
+	sort.Slice(e.Candidates, func(i, j int) bool { return e.Candidates[i].Seq < e.Candidates[j].Seq })
+
+	// the container injects from its own scope, the values of private providers are hidden there
+	nodes, hidden := x.visibleProviders(typ, nil)
+	visible, indexes := x.visibleObjects(typ, hidden)
+	for i, v := range x.objects[typ][ns] {
+		seq := x.origins[typ][ns][i]
+		e.Values = append(e.Values, ExplainedValue{Index: i, Value: v.Type().String(), Provider: funcs[seq], Seq: seq, Hidden: hidden[seq]})
+	}
+
+	var pending int
+	for _, n := range nodes {
+		if !x.initializer[n.fn] {
+			pending++
+		}
+	}
+
+	values := visible[ns]
+	if len(values) > 0 {
+		w := e.Values[indexes[ns][len(values)-1]]
+		e.Winner = &w
+	}
+
+	// a single value injection reads the default namespace, it fails on a missing or a null value
+	if _, err := selectValue(values); ns == defaultKey && err != nil {
+		e.Winner = nil
+
+		var fake reflect.Value
+		if len(values) == 0 && len(nodes) == 0 {
+			fake, _ = x.fakeOf(typ)
+		}
+
+		switch {
+		case len(values) == 0 && pending > 0:
+			// the next injection evaluates the pending providers first
+		case fake.IsValid():
+			e.Winner = &ExplainedValue{Index: -1, Value: fake.Type().String(), Provider: funcs[-1], Seq: -1}
+			e.Notes = append(e.Notes, "no visible provider, WithFakes resolves the type to the fake on the first injection")
+		default:
+			e.Error = err.Error()
+		}
+	}
+
+	if n := len(visible[ns]); n > 1 {
+		e.Notes = append(e.Notes, fmt.Sprintf("%d values in the namespace, a single value injection gets the last one, a list gets all of them", n))
+	}
+
+	switch {
+	case len(e.Candidates) == 0:
+		e.Notes = append(e.Notes, "no provider is registered for the type")
+	case len(nodes) == 0:
+		e.Notes = append(e.Notes, "every provider of the type is private to a module, the container can not inject it")
+	case len(visible[ns]) == 0 && ns != defaultKey:
+		e.Notes = append(e.Notes, "no value in the namespace, a single value injection only reads the default namespace")
+	}
+
+	for _, c := range e.Candidates {
+		if len(c.Dropped) > 0 {
+			e.Notes = append(e.Notes, fmt.Sprintf("#%d %s returned %d nil values which were dropped", c.Seq, c.Func, len(c.Dropped)))
+		}
+	}
+
+	if pending > 0 {
+		e.Notes = append(e.Notes, fmt.Sprintf("%d providers are not evaluated yet, the next injection of the type evaluates them and may change the winner", pending))
+	}
+
+	if len(visible[ns]) == 0 && len(nodes) > 0 && pending == 0 {
+		if e.AllowValuesNull {
+			e.Notes = append(e.Notes, "AllowValuesNull is set, a list or map injection gets an empty value, a single value injection still fails")
+		} else {
+			e.Notes = append(e.Notes, "AllowValuesNull is not set, any injection of the type fails with provider value not found")
+		}
+	}
+	return e
+}
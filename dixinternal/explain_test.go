@@ -0,0 +1,91 @@
+package dixinternal
+
+import (
+	"reflect"
+	"testing"
+)
+
+type testNotifier interface{ Notify() }
+
+type testMailNotifier struct{}
+
+func (testMailNotifier) Notify() {}
+
+func TestExplainLastWins(t *testing.T) {
+	di := New(WithSilentLog())
+	di.Provide(func() *testRedis { return &testRedis{Addr: "first"} })
+	di.Provide(func() *testRedis { return &testRedis{Addr: "last"} })
+
+	e := di.Explain(reflect.TypeOf(new(testRedis)), "")
+	if e.Winner != nil || e.Error != "" {
+		t.Fatalf("the providers are not evaluated yet, explanation=%s", e)
+	}
+
+	di.Inject(func(*testRedis) {})
+	e = di.Explain(reflect.TypeOf(new(testRedis)), "")
+	if len(e.Values) != 2 || e.Winner == nil || e.Winner.Index != 1 || e.Winner.Seq != e.Candidates[1].Seq {
+		t.Fatalf("the last value should win, explanation=%s", e)
+	}
+}
+
+func TestExplainPrivate(t *testing.T) {
+	di := New(WithSilentLog(), NewModule("storage",
+		Provide(func() *testRedis { return &testRedis{Addr: "public"} }),
+		Private(func() *testRedis { return &testRedis{Addr: "private"} }),
+		Invoke(func(*testRedis) {}),
+	))
+
+	e := di.Explain(reflect.TypeOf(new(testRedis)), "")
+	if len(e.Candidates) != 2 || !e.Candidates[1].Private || e.Candidates[1].Module != "storage" {
+		t.Fatalf("the private candidate should be marked, explanation=%s", e)
+	}
+
+	// the private value is the last one, the container injects the public one
+	if len(e.Values) != 2 || !e.Values[1].Hidden || e.Winner == nil || e.Winner.Index != 0 {
+		t.Fatalf("the private value should be hidden, explanation=%s", e)
+	}
+
+	var got string
+	di.Inject(func(r *testRedis) { got = r.Addr })
+	if got != "public" {
+		t.Fatalf("the explanation should match the injection, got=%s", got)
+	}
+}
+
+func TestExplainFake(t *testing.T) {
+	di := New(WithSilentLog(), WithFakes(func(typ reflect.Type) (reflect.Value, bool) {
+		return reflect.ValueOf(testMailNotifier{}), typ == reflect.TypeOf((*testNotifier)(nil)).Elem()
+	}))
+
+	typ := reflect.TypeOf((*testNotifier)(nil)).Elem()
+	e := di.Explain(typ, "")
+	if e.Winner == nil || e.Winner.Provider != "fake" || e.Error != "" {
+		t.Fatalf("the type should resolve to the fake, explanation=%s", e)
+	}
+
+	if _, ok := di.Fake(typ); ok {
+		t.Fatal("Explain should not create the fake")
+	}
+
+	di.Inject(func(testNotifier) {})
+	if e := di.Explain(typ, ""); e.Winner == nil || e.Winner.Provider != "fake" || e.Winner.Index != 0 {
+		t.Fatalf("the fake object should win, explanation=%s", e)
+	}
+}
+
+func TestExplainMissing(t *testing.T) {
+	di := New(WithSilentLog())
+	e := di.Explain(reflect.TypeOf(new(testRedis)), "")
+	if e.Winner != nil || e.Error != "provider value not found" {
+		t.Fatalf("the injection should fail, explanation=%s", e)
+	}
+
+	// a disabled provider runs but provides nothing
+	di.Provide(func() *testKafka { return new(testKafka) }, Profile("prod"))
+	_ = di.TryInject(func(*testKafka) {})
+
+	e = di.Explain(reflect.TypeOf(new(testKafka)), "")
+	if e.Winner != nil || e.Error != "provider value not found" || !e.Candidates[0].Disabled {
+		t.Fatalf("the injection should fail, explanation=%s", e)
+	}
+}
@@ -0,0 +1,70 @@
+package dixinternal
+
+import "reflect"
+
+// FakeFactory returns a fake of the interface type, ok is false when it has no fake of the type
+type FakeFactory func(typ reflect.Type) (fake reflect.Value, ok bool)
+
+// WithFakes turns on the test mode, an interface type without any provider is resolved to the fake of factory,
+// see dixtest.WithFakes
+func WithFakes(factory FakeFactory) Option {
+	return func(opts *Options) {
+		opts.Fakes = factory
+	}
+}
+
+// fake creates the fake object of the interface type without providers, it reports whether the type has a fake
+func (x *Dix) fake(typ reflect.Type) bool {
+	if x.option.Fakes == nil || typ.Kind() != reflect.Interface {
+		return false
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	if _, ok := x.fakes[typ]; ok {
+		return true
+	}
+
+	fake, ok := x.fakeOf(typ)
+	if !ok {
+		return false
+	}
+
+	// the object is stored as the interface type like a provider output
+	val := reflect.New(typ).Elem()
+	val.Set(fake)
+
+	x.log.Info("provider not found, use the fake", "type", typ.String(), "fake", fake.Type().String())
+	x.fakes[typ] = val
+	x.objects[typ] = map[group][]value{defaultKey: {val}}
+	x.origins[typ] = map[group][]int{defaultKey: {-1}}
+	return true
+}
+
+// fakeOf returns the fake of the interface type from the factory without storing it
+func (x *Dix) fakeOf(typ reflect.Type) (reflect.Value, bool) {
+	if x.option.Fakes == nil || typ.Kind() != reflect.Interface {
+		return reflect.Value{}, false
+	}
+
+	fake, ok := x.option.Fakes(typ)
+	if !ok {
+		return reflect.Value{}, false
+	}
+
+	if !fake.IsValid() || !fake.Type().Implements(typ) {
+		x.log.Error("invalid fake, it does not implement the type", "type", typ.String())
+		return reflect.Value{}, false
+	}
+	return fake, true
+}
+
+// Fake returns the fake object created for the interface type in the test mode, see WithFakes
+func (x *Dix) Fake(typ reflect.Type) (reflect.Value, bool) {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	fake, ok := x.fakes[typ]
+	return fake, ok
+}
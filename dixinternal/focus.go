@@ -0,0 +1,190 @@
+package dixinternal
+
+type FocusDirection string
+
+const (
+	// FocusAncestors walks to the dependencies, the providers and input types the focus is built from
+	FocusAncestors FocusDirection = "ancestors"
+
+	// FocusDescendants walks to the dependents, the providers and output types built from the focus
+	FocusDescendants FocusDirection = "descendants"
+)
+
+// GraphFocus limits the rendered graphs to the part reachable from a type or a provider
+type GraphFocus struct {
+	// Name is a type name like *main.DB, qualified like *example.com/app/db.DB when the short one is ambiguous,
+	// or a provider func like main.NewDB
+	Name      string
+	Direction FocusDirection
+
+	// Depth is the number of providers to walk through, 0 means no limit
+	Depth int
+}
+
+// Ancestors renders only the dependencies of the type or provider name up to depth providers away,
+// combine it with Descendants to render both sides
+func Ancestors(name string, depth int) GraphOption {
+	return func(opts *GraphOptions) {
+		opts.Focus = append(opts.Focus, GraphFocus{Name: name, Direction: FocusAncestors, Depth: depth})
+	}
+}
+
+// Descendants renders only the dependents of the type or provider name up to depth providers away
+func Descendants(name string, depth int) GraphOption {
+	return func(opts *GraphOptions) {
+		opts.Focus = append(opts.Focus, GraphFocus{Name: name, Direction: FocusDescendants, Depth: depth})
+	}
+}
+
+// ClusterByPackage groups the providers by the Go package of the provider func
+func ClusterByPackage() GraphOption {
+	return func(opts *GraphOptions) {
+		opts.ClusterByPackage = true
+	}
+}
+
+// ClusterByModule groups the providers by the module which registered them and the plugins by registry,
+// it takes precedence over ClusterByPackage
+func ClusterByModule() GraphOption {
+	return func(opts *GraphOptions) {
+		opts.ClusterByModule = true
+	}
+}
+
+// Highlight colors the missing types, the failed providers and the providers which returned nil values
+func Highlight() GraphOption {
+	return func(opts *GraphOptions) {
+		opts.Highlight = true
+	}
+}
+
+// focusNodes returns the type and provider nodes matching the focus name
+func (g *GraphModel) focusNodes(name string) []*GraphNode {
+	var nodes []*GraphNode
+	for _, n := range g.Nodes {
+		switch n.Kind {
+		case NodeType:
+			if n.Label == name || n.Type == name {
+				nodes = append(nodes, n)
+			}
+		case NodeProvider:
+			if n.Provider.Func == name || n.Label == name {
+				nodes = append(nodes, n)
+			}
+		}
+	}
+	return nodes
+}
+
+// focus returns the part of g reachable from the focus, with the namespaces and objects of the kept types,
+// g itself without focus
+func focus(g *GraphModel, focus []GraphFocus) *GraphModel {
+	if len(focus) == 0 {
+		return g
+	}
+
+	keep := make(map[string]bool)
+	for _, f := range focus {
+		for _, start := range g.focusNodes(f.Name) {
+			walkFocus(g, start, f, keep)
+		}
+	}
+
+	var nodes []*GraphNode
+	for _, n := range g.Nodes {
+		switch n.Kind {
+		case NodeNamespace, NodeObject:
+			if keep[TypeNodeID(n.Type)] {
+				keep[n.ID] = true
+			}
+		}
+
+		if keep[n.ID] {
+			nodes = append(nodes, n)
+		}
+	}
+
+	var edges []*GraphEdge
+	for _, e := range g.Edges {
+		if keep[e.From] && keep[e.To] {
+			edges = append(edges, e)
+		}
+	}
+	return NewGraphModel(nodes, edges)
+}
+
+// walkFocus adds the nodes within f.Depth providers of start to keep, a provider start is the first provider
+func walkFocus(g *GraphModel, start *GraphNode, f GraphFocus, keep map[string]bool) {
+	hops := make(map[string]int)
+	hops[start.ID] = 0
+	if start.Kind == NodeProvider {
+		hops[start.ID] = 1
+
+		// keep the types on the other side of the provider, they tell what it is for
+		if f.Direction == FocusDescendants {
+			for _, e := range g.EdgesTo(start.ID) {
+				keep[e.From] = true
+			}
+		} else {
+			for _, e := range g.EdgesFrom(start.ID) {
+				keep[e.To] = true
+			}
+		}
+	}
+
+	queue := []string{start.ID}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		keep[id] = true
+
+		var next []string
+		if f.Direction == FocusDescendants {
+			for _, e := range g.EdgesFrom(id) {
+				next = append(next, e.To)
+			}
+		} else {
+			for _, e := range g.EdgesTo(id) {
+				next = append(next, e.From)
+			}
+		}
+
+		for _, to := range next {
+			n := g.Node(to)
+			if n.Kind != NodeType && n.Kind != NodeProvider {
+				continue
+			}
+
+			h := hops[id]
+			if n.Kind == NodeProvider {
+				h++
+			}
+
+			if f.Depth > 0 && h > f.Depth {
+				continue
+			}
+
+			if old, ok := hops[to]; ok && old <= h {
+				continue
+			}
+
+			hops[to] = h
+			queue = append(queue, to)
+		}
+	}
+}
+
+// highlight returns the node attrs of a highlighted node, or nil
+func highlight(n *GraphNode) map[string]string {
+	switch {
+	case n.Kind == NodeType && n.Missing:
+		return map[string]string{"fillcolor": "#F8D7DA", "color": "#C0392B"}
+	case n.Kind == NodeType && n.NilValue:
+		return map[string]string{"fillcolor": "#FFE8CC", "color": "#E67E22"}
+	case n.Kind == NodeProvider && n.Provider.Error != "":
+		return map[string]string{"fillcolor": "#F8D7DA", "color": "#C0392B"}
+	case n.Kind == NodeProvider && len(n.Provider.Dropped) > 0:
+		return map[string]string{"fillcolor": "#FFE8CC", "color": "#E67E22"}
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package dixinternal
+
+import (
+	"errors"
+	"slices"
+	"sort"
+	"testing"
+)
+
+type (
+	focusA struct{}
+	focusB struct{}
+	focusC struct{}
+	focusD struct{}
+	focusE struct{}
+)
+
+// newFocusGraph wires A -> B -> C -> D and B -> E
+func newFocusGraph() *GraphModel {
+	di := New(WithSilentLog())
+	di.Provide(func() *focusA { return new(focusA) })
+	di.Provide(func(*focusA) *focusB { return new(focusB) })
+	di.Provide(func(*focusB) *focusC { return new(focusC) })
+	di.Provide(func(*focusC) *focusD { return new(focusD) })
+	di.Provide(func(*focusB) *focusE { return new(focusE) })
+	return di.Inspect()
+}
+
+// focusTypes returns the labels of the type nodes kept by the focus
+func focusTypes(g *GraphModel, f ...GraphFocus) []string {
+	var types []string
+	for _, n := range focus(g, f).NodesOf(NodeType) {
+		types = append(types, n.Label)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// providerOf returns the label of the provider node of the type label
+func providerOf(g *GraphModel, typ string) string {
+	for _, n := range g.NodesOf(NodeProvider) {
+		if slices.Contains(n.Provider.Outputs, typ) {
+			return n.Label
+		}
+	}
+	return ""
+}
+
+func TestFocusDepth(t *testing.T) {
+	g := newFocusGraph()
+	for _, tt := range []struct {
+		depth int
+		want  []string
+	}{
+		{1, []string{"*dixinternal.focusC", "*dixinternal.focusD"}},
+		{2, []string{"*dixinternal.focusB", "*dixinternal.focusC", "*dixinternal.focusD"}},
+		{0, []string{"*dixinternal.focusA", "*dixinternal.focusB", "*dixinternal.focusC", "*dixinternal.focusD"}},
+	} {
+		got := focusTypes(g, GraphFocus{Name: "*dixinternal.focusD", Direction: FocusAncestors, Depth: tt.depth})
+		if !slices.Equal(got, tt.want) {
+			t.Fatalf("depth=%d types=%v, want %v", tt.depth, got, tt.want)
+		}
+	}
+
+	// the qualified name focuses the same type
+	got := focusTypes(g, GraphFocus{Name: "*github.com/pubgo/dix/dixinternal.focusD", Direction: FocusAncestors, Depth: 1})
+	if want := []string{"*dixinternal.focusC", "*dixinternal.focusD"}; !slices.Equal(got, want) {
+		t.Fatalf("types=%v, want %v", got, want)
+	}
+}
+
+func TestFocusBothDirections(t *testing.T) {
+	g := newFocusGraph()
+	got := focusTypes(g,
+		GraphFocus{Name: "*dixinternal.focusC", Direction: FocusAncestors},
+		GraphFocus{Name: "*dixinternal.focusC", Direction: FocusDescendants},
+	)
+
+	// E is a dependent of B, not of C
+	want := []string{"*dixinternal.focusA", "*dixinternal.focusB", "*dixinternal.focusC", "*dixinternal.focusD"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("types=%v, want %v", got, want)
+	}
+
+	var opt GraphOptions
+	Ancestors("*dixinternal.focusC", 0)(&opt)
+	Descendants("*dixinternal.focusC", 0)(&opt)
+	if !slices.Equal(focusTypes(g, opt.Focus...), want) {
+		t.Fatal("the options should combine the focus")
+	}
+}
+
+func TestFocusProvider(t *testing.T) {
+	g := newFocusGraph()
+	name := providerOf(g, "*dixinternal.focusC")
+
+	// a provider start is the first provider, its inputs tell what it is built from
+	f := focus(g, []GraphFocus{{Name: name, Direction: FocusDescendants, Depth: 1}})
+	got := focusTypes(g, GraphFocus{Name: name, Direction: FocusDescendants, Depth: 1})
+	if want := []string{"*dixinternal.focusB", "*dixinternal.focusC"}; !slices.Equal(got, want) {
+		t.Fatalf("types=%v, want %v", got, want)
+	}
+
+	providers := f.NodesOf(NodeProvider)
+	if len(providers) != 1 || providers[0].Label != name {
+		t.Fatalf("only the focused provider should be kept, providers=%d", len(providers))
+	}
+
+	got = focusTypes(g, GraphFocus{Name: name, Direction: FocusAncestors, Depth: 1})
+	if want := []string{"*dixinternal.focusB", "*dixinternal.focusC"}; !slices.Equal(got, want) {
+		t.Fatalf("types=%v, want %v", got, want)
+	}
+}
+
+func TestGraphNodesCluster(t *testing.T) {
+	di := New(WithSilentLog(), NewModule("storage", Provide(func() *focusA { return new(focusA) })))
+	di.Provide(func(*focusA) *focusB { return new(focusB) })
+	g := di.Inspect()
+
+	clusters := func(opt *GraphOptions) map[string]string {
+		m := make(map[string]string)
+		for _, n := range graphNodes(g, opt, NodeProvider) {
+			m[n.name] = n.cluster
+		}
+		return m
+	}
+
+	a, b := providerOf(g, "*dixinternal.focusA"), providerOf(g, "*dixinternal.focusB")
+	got := clusters(&GraphOptions{ClusterByModule: true, ClusterByPackage: true})
+	if got[a] != "module storage" {
+		t.Fatalf("the module should take precedence over the package, cluster=%q", got[a])
+	}
+	if got[b] != "github.com/pubgo/dix/dixinternal" {
+		t.Fatalf("a provider out of modules should be clustered by package, cluster=%q", got[b])
+	}
+
+	got = clusters(&GraphOptions{ClusterByModule: true})
+	if _, ok := got[b]; ok || got[a] != "module storage" {
+		t.Fatalf("only the module providers should be clustered, clusters=%v", got)
+	}
+}
+
+func TestGraphNodesHighlight(t *testing.T) {
+	di := New(WithSilentLog())
+	di.Provide(func(*focusA) *focusB { return new(focusB) })
+	di.Provide(func() (*focusC, error) { return nil, errors.New("dial failed") })
+	di.Provide(func() *focusD { return nil })
+	di.Provide(func() *focusE { return new(focusE) })
+	_ = di.TryInject(func(*focusC) {})
+	_ = di.TryInject(func(*focusD) {})
+	_ = di.TryInject(func(*focusE) {})
+	g := di.Inspect()
+
+	attrs := make(map[string]map[string]string)
+	for _, n := range graphNodes(g, &GraphOptions{Highlight: true}, NodeType, NodeProvider) {
+		attrs[n.name] = n.attrs
+	}
+
+	red, orange := "#C0392B", "#E67E22"
+	for name, want := range map[string]string{
+		"*dixinternal.focusA":                red,
+		providerOf(g, "*dixinternal.focusC"): red,
+		"*dixinternal.focusD":                orange,
+		providerOf(g, "*dixinternal.focusD"): orange,
+	} {
+		if attrs[name]["color"] != want {
+			t.Fatalf("%s should be highlighted with %s, attrs=%v", name, want, attrs[name])
+		}
+	}
+
+	if _, ok := attrs["*dixinternal.focusE"]; ok {
+		t.Fatal("a healthy type should not be highlighted")
+	}
+
+	if nodes := graphNodes(g, &GraphOptions{}, NodeType, NodeProvider); len(nodes) != 0 {
+		t.Fatalf("the nodes need no declaration without highlight, nodes=%d", len(nodes))
+	}
+}
@@ -0,0 +1,380 @@
+package dixinternal
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pubgo/funk/stack"
+)
+
+type NodeKind string
+
+const (
+	NodeType      NodeKind = "type"
+	NodeProvider  NodeKind = "provider"
+	NodeNamespace NodeKind = "namespace"
+	NodeObject    NodeKind = "object"
+)
+
+type EdgeKind string
+
+const (
+	// EdgeInput type -> provider, the provider consumes the type
+	EdgeInput EdgeKind = "input"
+
+	// EdgeOutput provider -> type, the provider produces the type
+	EdgeOutput EdgeKind = "output"
+
+	// EdgeNamespace type -> namespace, the type has values in the namespace
+	EdgeNamespace EdgeKind = "namespace"
+
+	// EdgeObject namespace -> object, the namespace holds the instantiated object
+	EdgeObject EdgeKind = "object"
+)
+
+// GraphModel is the dependency graph of a Dix, node ids are
+//
+//	type:<type>
+//	provider:<seq>
+//	namespace:<type>#<namespace>
+//	object:<type>#<namespace>#<index>
+//
+// where <type> is the type name qualified by the package path, e.g. *example.com/app/db.Client, see TypeName.
+// A GraphModel is immutable, Node and the edge lookups read the index built with it,
+// so it can be shared by goroutines. Build a changed graph with NewGraphModel instead of changing Nodes or Edges.
+type GraphModel struct {
+	Nodes []*GraphNode `json:"nodes"`
+	Edges []*GraphEdge `json:"edges"`
+
+	index *graphIndex
+}
+
+// graphIndex is the lookup of the nodes and edges by id
+type graphIndex struct {
+	byID     map[string]*GraphNode
+	from, to map[string][]*GraphEdge
+}
+
+// NewGraphModel returns the graph of the nodes and edges with its index
+func NewGraphModel(nodes []*GraphNode, edges []*GraphEdge) *GraphModel {
+	g := &GraphModel{Nodes: nodes, Edges: edges}
+	g.index = newGraphIndex(g)
+	return g
+}
+
+func newGraphIndex(g *GraphModel) *graphIndex {
+	idx := &graphIndex{
+		byID: make(map[string]*GraphNode, len(g.Nodes)),
+		from: make(map[string][]*GraphEdge),
+		to:   make(map[string][]*GraphEdge),
+	}
+	for _, n := range g.Nodes {
+		if _, ok := idx.byID[n.ID]; !ok {
+			idx.byID[n.ID] = n
+		}
+	}
+	for _, e := range g.Edges {
+		idx.from[e.From] = append(idx.from[e.From], e)
+		idx.to[e.To] = append(idx.to[e.To], e)
+	}
+	return idx
+}
+
+// lookup returns the index of g, a model which was not built by NewGraphModel, e.g. decoded from JSON,
+// is indexed on every lookup
+func (g *GraphModel) lookup() *graphIndex {
+	if g.index != nil {
+		return g.index
+	}
+	return newGraphIndex(g)
+}
+
+type GraphNode struct {
+	ID    string   `json:"id"`
+	Kind  NodeKind `json:"kind"`
+	Label string   `json:"label"`
+
+	// Type is the qualified name of the type of a type node, or of the type an object, namespace or provider output
+	// belongs to, Label is the short type name of a type node
+	Type      string        `json:"type,omitempty"`
+	Namespace string        `json:"namespace,omitempty"`
+	Provider  *ProviderInfo `json:"provider,omitempty"`
+
+	// Missing marks a type which is consumed but has no provider,
+	// NilValue marks a type a provider returned nil for, see ProviderInfo.Dropped
+	Missing  bool `json:"missing,omitempty"`
+	NilValue bool `json:"nil_value,omitempty"`
+}
+
+type GraphEdge struct {
+	From string   `json:"from"`
+	To   string   `json:"to"`
+	Kind EdgeKind `json:"kind"`
+
+	// Via is the parameter object path an input comes through
+	Via  string `json:"via,omitempty"`
+	Map  bool   `json:"map,omitempty"`
+	List bool   `json:"list,omitempty"`
+}
+
+type ProviderInfo struct {
+	Func     string   `json:"func"`
+	Package  string   `json:"package"`
+	Location string   `json:"location"`
+	Seq      int      `json:"seq"`
+	Outputs  []string `json:"outputs"`
+	Inputs   []string `json:"inputs,omitempty"`
+
+	// Module is the name of the module which registered the provider, Private providers are visible only in it
+	Module  string `json:"module,omitempty"`
+	Private bool   `json:"private,omitempty"`
+
+	// Plugin is the registry entry the provider was enabled from, like codec/json, see Register
+	Plugin string `json:"plugin,omitempty"`
+
+	// Disabled marks a provider whose conditions did not hold, it provides no value
+	Disabled bool `json:"disabled,omitempty"`
+
+	Initialized bool          `json:"initialized"`
+	Error       string        `json:"error,omitempty"`
+	StartedAt   time.Time     `json:"started_at,omitempty"`
+	Cost        time.Duration `json:"cost,omitempty"`
+
+	// Dropped are the nil values removed from the provider output
+	Dropped []DroppedValue `json:"dropped,omitempty"`
+}
+
+// Node returns the node of the id, or nil
+func (g *GraphModel) Node(id string) *GraphNode {
+	return g.lookup().byID[id]
+}
+
+// NodesOf returns the nodes of the kind in graph order
+func (g *GraphModel) NodesOf(kind NodeKind) []*GraphNode {
+	var nodes []*GraphNode
+	for _, n := range g.Nodes {
+		if n.Kind == kind {
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes
+}
+
+// EdgesFrom returns the edges leaving the node
+func (g *GraphModel) EdgesFrom(id string) []*GraphEdge {
+	return g.lookup().from[id]
+}
+
+// EdgesTo returns the edges entering the node
+func (g *GraphModel) EdgesTo(id string) []*GraphEdge {
+	return g.lookup().to[id]
+}
+
+// funcPackage returns the import path of the package of a func frame, frame.Pkg of a method or closure
+// keeps the receiver or the enclosing func, e.g. example.com/app.(*Server) or main.main
+func funcPackage(pkg string) string {
+	slash := strings.LastIndex(pkg, "/")
+	if dot := strings.Index(pkg[slash+1:], "."); dot >= 0 {
+		return pkg[:slash+1+dot]
+	}
+	return pkg
+}
+
+// TypeName returns the type name qualified by the package path, which tells apart the types of packages with the same name
+func TypeName(typ reflect.Type) string {
+	if typ.Name() != "" {
+		if typ.PkgPath() == "" {
+			return typ.String()
+		}
+		return typ.PkgPath() + "." + typ.Name()
+	}
+
+	switch typ.Kind() {
+	case reflect.Pointer:
+		return "*" + TypeName(typ.Elem())
+	case reflect.Slice:
+		return "[]" + TypeName(typ.Elem())
+	case reflect.Array:
+		return fmt.Sprintf("[%d]%s", typ.Len(), TypeName(typ.Elem()))
+	case reflect.Map:
+		return "map[" + TypeName(typ.Key()) + "]" + TypeName(typ.Elem())
+	default:
+		return typ.String()
+	}
+}
+
+// TypeNodeID returns the node id of the qualified type name, see TypeName
+func TypeNodeID(typ string) string { return "type:" + typ }
+
+func ProviderNodeID(seq int) string { return fmt.Sprintf("provider:%d", seq) }
+
+func NamespaceNodeID(typ, ns string) string { return fmt.Sprintf("namespace:%s#%s", typ, ns) }
+
+func ObjectNodeID(typ, ns string, index int) string {
+	return fmt.Sprintf("object:%s#%s#%d", typ, ns, index)
+}
+
+// inspect builds the graph model, types are sorted by name, providers by registration order, x.mu must be held
+func (x *Dix) inspect() *GraphModel {
+	// types maps the qualified names to the labels
+	types := make(map[string]string)
+	nilTypes := make(map[string]bool)
+	addType := func(typ reflect.Type) string {
+		types[TypeName(typ)] = typ.String()
+		return TypeNodeID(TypeName(typ))
+	}
+
+	var providers []*providerFn
+	for _, nodes := range x.providers {
+		providers = append(providers, nodes...)
+	}
+	sort.SliceStable(providers, func(i, j int) bool {
+		if providers[i].seq != providers[j].seq {
+			return providers[i].seq < providers[j].seq
+		}
+		return providers[i].output.typ.String() < providers[j].output.typ.String()
+	})
+
+	var providerNodes []*GraphNode
+	var edges []*GraphEdge
+	bySeq := make(map[int]*GraphNode)
+	for _, n := range providers {
+		id := ProviderNodeID(n.seq)
+		node := bySeq[n.seq]
+		if node == nil {
+			frame := stack.CallerWithFunc(n.fn)
+			info := &ProviderInfo{
+				Func:        frame.Pkg + "." + frame.Name,
+				Package:     funcPackage(frame.Pkg),
+				Location:    fmt.Sprintf("%s:%d", frame.File, frame.Line),
+				Seq:         n.seq,
+				Initialized: x.initializer[n.fn],
+				Private:     n.private,
+				Disabled:    x.disabled[n.fn],
+			}
+
+			if n.module != nil {
+				info.Module = n.module.name
+			}
+
+			label := frame.Short()
+			if n.plugin != nil {
+				info.Plugin = n.plugin.String()
+				label = fmt.Sprintf("[%s] %s", info.Plugin, label)
+			}
+
+			if stat := x.initStats[n.fn]; stat != nil {
+				info.StartedAt = stat.startedAt
+				info.Cost = stat.cost
+				info.Dropped = stat.dropped
+				if stat.err != nil {
+					info.Error = stat.err.Error()
+				}
+
+				for _, d := range stat.dropped {
+					nilTypes[d.Type] = true
+				}
+			}
+
+			node = &GraphNode{ID: id, Kind: NodeProvider, Label: label, Provider: info}
+			bySeq[n.seq] = node
+			providerNodes = append(providerNodes, node)
+
+			// the fields of a struct output share the inputs
+			for _, in := range n.inputList {
+				walkProvideInputs(x.log, in.typ, "", func(input *providerInputType, via string) {
+					info.Inputs = append(info.Inputs, input.typ.String())
+					edges = append(edges, &GraphEdge{
+						From: addType(input.typ),
+						To:   id,
+						Kind: EdgeInput,
+						Via:  via,
+						Map:  in.isMap,
+						List: in.isList,
+					})
+				})
+			}
+		}
+
+		node.Provider.Outputs = append(node.Provider.Outputs, n.output.typ.String())
+		if node.Type == "" {
+			node.Type = TypeName(n.output.typ)
+		}
+
+		edges = append(edges, &GraphEdge{
+			From: id,
+			To:   addType(n.output.typ),
+			Kind: EdgeOutput,
+			Map:  n.output.isMap,
+			List: n.output.isList,
+		})
+	}
+
+	var objectNodes []*GraphNode
+	for typ, objects := range x.objects {
+		typeID, name := addType(typ), TypeName(typ)
+		for ns, values := range objects {
+			nsID := NamespaceNodeID(name, ns)
+			objectNodes = append(objectNodes, &GraphNode{ID: nsID, Kind: NodeNamespace, Label: ns, Type: name, Namespace: ns})
+			edges = append(edges, &GraphEdge{From: typeID, To: nsID, Kind: EdgeNamespace})
+
+			for i, v := range values {
+				objID := ObjectNodeID(name, ns, i)
+				objectNodes = append(objectNodes, &GraphNode{ID: objID, Kind: NodeObject, Label: v.Type().String(), Type: name, Namespace: ns})
+				edges = append(edges, &GraphEdge{From: nsID, To: objID, Kind: EdgeObject})
+			}
+		}
+	}
+
+	var typeNames []string
+	for name := range types {
+		typeNames = append(typeNames, name)
+	}
+	sort.Strings(typeNames)
+
+	produced := make(map[string]bool)
+	for _, e := range edges {
+		if e.Kind == EdgeOutput {
+			produced[e.To] = true
+		}
+	}
+
+	var nodes []*GraphNode
+	for _, name := range typeNames {
+		id, label := TypeNodeID(name), types[name]
+		nodes = append(nodes, &GraphNode{ID: id, Kind: NodeType, Label: label, Type: name, Missing: !produced[id], NilValue: nilTypes[label]})
+	}
+	nodes = append(nodes, providerNodes...)
+
+	// object ids keep the value order of a namespace, sort by namespace and index rather than by id
+	sort.SliceStable(objectNodes, func(i, j int) bool {
+		a, b := objectNodes[i], objectNodes[j]
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Kind == NodeNamespace && b.Kind != NodeNamespace
+	})
+	nodes = append(nodes, objectNodes...)
+
+	rank := make(map[string]int, len(nodes))
+	for i, n := range nodes {
+		rank[n.ID] = i
+	}
+	sort.SliceStable(edges, func(i, j int) bool {
+		a, b := edges[i], edges[j]
+		if rank[a.From] != rank[b.From] {
+			return rank[a.From] < rank[b.From]
+		}
+		if rank[a.To] != rank[b.To] {
+			return rank[a.To] < rank[b.To]
+		}
+		return a.Via < b.Via
+	})
+	return NewGraphModel(nodes, edges)
+}
@@ -0,0 +1,107 @@
+package dixinternal
+
+import (
+	htmltemplate "html/template"
+	"reflect"
+	"sync"
+	"testing"
+	texttemplate "text/template"
+)
+
+func TestGraphModelLookup(t *testing.T) {
+	di := New(WithSilentLog())
+	di.Provide(func() *testDB { return new(testDB) })
+	di.Provide(func(*testDB) *testCache { return new(testCache) })
+
+	g := di.Inspect()
+	db := g.Node(TypeNodeID("*github.com/pubgo/dix/dixinternal.testDB"))
+	if db == nil || db.Kind != NodeType || db.Label != "*dixinternal.testDB" {
+		t.Fatalf("the type node should be found, node=%+v", db)
+	}
+
+	if len(g.EdgesTo(db.ID)) != 1 || len(g.EdgesFrom(db.ID)) != 1 {
+		t.Fatalf("the type should have one provider and one dependent, to=%d from=%d",
+			len(g.EdgesTo(db.ID)), len(g.EdgesFrom(db.ID)))
+	}
+
+	// a changed graph is a new model, the same number of nodes does not hide the change
+	nodes := append([]*GraphNode(nil), g.Nodes...)
+	nodes[0] = &GraphNode{ID: "extra", Kind: NodeType, Label: "extra"}
+	changed := NewGraphModel(nodes, g.Edges)
+	if changed.Node("extra") == nil || changed.Node(g.Nodes[0].ID) != nil {
+		t.Fatal("the new model should index its own nodes")
+	}
+
+	if g.Node("extra") != nil || g.Node(g.Nodes[0].ID) == nil {
+		t.Fatal("the model should not change")
+	}
+
+	// a decoded model has no index
+	decoded := &GraphModel{Nodes: g.Nodes, Edges: g.Edges}
+	if decoded.Node(db.ID) != db || len(decoded.EdgesFrom(db.ID)) != 1 {
+		t.Fatal("the model without index should be looked up too")
+	}
+}
+
+func TestGraphModelConcurrentLookup(t *testing.T) {
+	di := New(WithSilentLog())
+	di.Provide(func() *testDB { return new(testDB) })
+	di.Provide(func(*testDB) *testCache { return new(testCache) })
+
+	g := di.Inspect()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, n := range g.Nodes {
+				if g.Node(n.ID) == nil {
+					t.Errorf("node %s not found", n.ID)
+				}
+				_ = g.EdgesFrom(n.ID)
+				_ = g.EdgesTo(n.ID)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestGraphTypeNodeID(t *testing.T) {
+	di := New(WithSilentLog())
+	di.Provide(func() *texttemplate.Template { return texttemplate.New("text") })
+	di.Provide(func() *htmltemplate.Template { return htmltemplate.New("html") })
+
+	g := di.Inspect()
+	text := g.Node(TypeNodeID(TypeName(reflect.TypeOf(new(texttemplate.Template)))))
+	html := g.Node(TypeNodeID(TypeName(reflect.TypeOf(new(htmltemplate.Template)))))
+	if text == nil || html == nil || text == html {
+		t.Fatalf("the types of packages with the same name should have their own nodes, text=%+v html=%+v", text, html)
+	}
+
+	if text.ID != "type:*text/template.Template" || text.Label != "*template.Template" || text.Label != html.Label {
+		t.Fatalf("the id should be qualified and the label short, text=%+v html=%+v", text, html)
+	}
+
+	for _, n := range g.NodesOf(NodeProvider) {
+		if len(g.EdgesFrom(n.ID)) != 1 {
+			t.Fatalf("each provider should output its own type, provider=%s", n.Label)
+		}
+	}
+}
+
+func TestTypeName(t *testing.T) {
+	for _, tt := range []struct {
+		typ  reflect.Type
+		want string
+	}{
+		{reflect.TypeOf(new(testDB)), "*github.com/pubgo/dix/dixinternal.testDB"},
+		{reflect.TypeOf([]*testDB{}), "[]*github.com/pubgo/dix/dixinternal.testDB"},
+		{reflect.TypeOf(map[string][2]*testDB{}), "map[string][2]*github.com/pubgo/dix/dixinternal.testDB"},
+		{reflect.TypeOf((*error)(nil)).Elem(), "error"},
+		{reflect.TypeOf(func() {}), "func()"},
+	} {
+		if got := TypeName(tt.typ); got != tt.want {
+			t.Errorf("TypeName(%s)=%s, want %s", tt.typ, got, tt.want)
+		}
+	}
+}
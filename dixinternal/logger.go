@@ -0,0 +1,99 @@
+package dixinternal
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// Logger is the logging interface of a Dix, kv are alternating keys and values, see WithLogger
+type Logger interface {
+	Debug(msg string, kv ...any)
+	Info(msg string, kv ...any)
+	Warn(msg string, kv ...any)
+	Error(msg string, kv ...any)
+}
+
+// funkLogger writes to the package logger, which can be replaced by SetLog
+type funkLogger struct{}
+
+func (funkLogger) Debug(msg string, kv ...any) { logKV(logger.Debug(), msg, kv) }
+func (funkLogger) Info(msg string, kv ...any)  { logKV(logger.Info(), msg, kv) }
+func (funkLogger) Warn(msg string, kv ...any)  { logKV(logger.Warn(), msg, kv) }
+func (funkLogger) Error(msg string, kv ...any) { logKV(logger.Error(), msg, kv) }
+
+// logKV adds kv to the log event and sends it
+func logKV[E interface {
+	Any(key string, val any) E
+	Msg(msg string)
+}](e E, msg string, kv []any) {
+	for i := 0; i+1 < len(kv); i += 2 {
+		e = e.Any(fmt.Sprint(kv[i]), kv[i+1])
+	}
+	e.Msg(msg)
+}
+
+// SlogLogger adapts a *slog.Logger
+func SlogLogger(l *slog.Logger) Logger {
+	return slogLogger{l: l}
+}
+
+type slogLogger struct {
+	l *slog.Logger
+}
+
+func (s slogLogger) Debug(msg string, kv ...any) { s.l.Debug(msg, kv...) }
+func (s slogLogger) Info(msg string, kv ...any)  { s.l.Info(msg, kv...) }
+func (s slogLogger) Warn(msg string, kv ...any)  { s.l.Warn(msg, kv...) }
+func (s slogLogger) Error(msg string, kv ...any) { s.l.Error(msg, kv...) }
+
+// NopLogger discards everything
+type NopLogger struct{}
+
+func (NopLogger) Debug(string, ...any) {}
+func (NopLogger) Info(string, ...any)  {}
+func (NopLogger) Warn(string, ...any)  {}
+func (NopLogger) Error(string, ...any) {}
+
+// levelLogger drops the records below min
+type levelLogger struct {
+	Logger
+	min slog.Level
+}
+
+func (l levelLogger) enabled(level slog.Level) bool { return level >= l.min }
+
+func (l levelLogger) Debug(msg string, kv ...any) {
+	if l.enabled(slog.LevelDebug) {
+		l.Logger.Debug(msg, kv...)
+	}
+}
+
+func (l levelLogger) Info(msg string, kv ...any) {
+	if l.enabled(slog.LevelInfo) {
+		l.Logger.Info(msg, kv...)
+	}
+}
+
+func (l levelLogger) Warn(msg string, kv ...any) {
+	if l.enabled(slog.LevelWarn) {
+		l.Logger.Warn(msg, kv...)
+	}
+}
+
+func (l levelLogger) Error(msg string, kv ...any) {
+	if l.enabled(slog.LevelError) {
+		l.Logger.Error(msg, kv...)
+	}
+}
+
+func newLogger(opt Options) Logger {
+	var log Logger = funkLogger{}
+	if opt.Logger != nil {
+		log = opt.Logger
+	}
+
+	if opt.LogLevel != nil {
+		log = levelLogger{Logger: log, min: *opt.LogLevel}
+	}
+	return log
+}
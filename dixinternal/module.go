@@ -0,0 +1,197 @@
+package dixinternal
+
+import (
+	"reflect"
+	"slices"
+
+	"github.com/pubgo/funk/errors"
+)
+
+// Module is a named group of providers, invocations and included modules, see NewModule
+type Module struct {
+	name  string
+	items []moduleItem
+}
+
+func (m *Module) Name() string { return m.name }
+
+type moduleItem struct {
+	fn      any
+	conds   []Condition
+	private bool
+	invoke  bool
+	include *Module
+}
+
+type ModuleOption func(m *Module)
+
+// NewModule returns the Option which installs the module into the container, pass it to New or to Include.
+// The items are installed in order, a module installed or included more than once is installed the first time only.
+// The invocations of all modules run after every module is installed.
+func NewModule(name string, opts ...ModuleOption) Option {
+	m := &Module{name: name}
+	for i := range opts {
+		opts[i](m)
+	}
+
+	return func(opts *Options) {
+		opts.Modules = append(opts.Modules, m)
+	}
+}
+
+// Provide registers the providers in the module, the Condition arguments apply to every provider of the call.
+// With a *Dix as the first argument the providers are registered into the container right away like Dix.Provide,
+// the returned option can not be used in a module then
+func Provide(args ...any) ModuleOption {
+	if len(args) > 0 {
+		if di, ok := args[0].(*Dix); ok {
+			for _, item := range providerItems(args[1:], false) {
+				di.provideIn(item.fn, providerScope{conds: item.conds})
+			}
+
+			return func(m *Module) {
+				panic(errors.Errorf("the providers are already registered into a container, module=%s", m.name))
+			}
+		}
+	}
+
+	return func(m *Module) {
+		m.items = append(m.items, providerItems(args, false)...)
+	}
+}
+
+// Private registers the providers in the module, their values are injected only into the providers and
+// invocations of the same module
+func Private(fns ...any) ModuleOption {
+	return func(m *Module) {
+		m.items = append(m.items, providerItems(fns, true)...)
+	}
+}
+
+func providerItems(fns []any, private bool) []moduleItem {
+	var conds []Condition
+	for _, fn := range fns {
+		if c, ok := fn.(Condition); ok {
+			conds = append(conds, c)
+		}
+	}
+
+	var items []moduleItem
+	for _, fn := range fns {
+		if _, ok := fn.(Condition); !ok {
+			items = append(items, moduleItem{fn: fn, conds: conds, private: private})
+		}
+	}
+	return items
+}
+
+// Invoke injects the funcs or struct pointers in the scope of the module when the container is created
+func Invoke(fns ...any) ModuleOption {
+	return func(m *Module) {
+		for _, fn := range fns {
+			m.items = append(m.items, moduleItem{fn: fn, invoke: true})
+		}
+	}
+}
+
+// Include installs the modules of NewModule before the following items
+func Include(modules ...Option) ModuleOption {
+	return func(m *Module) {
+		var opts Options
+		for i := range modules {
+			modules[i](&opts)
+		}
+
+		for _, inc := range opts.Modules {
+			m.items = append(m.items, moduleItem{include: inc})
+		}
+	}
+}
+
+// withModule makes the injection see the private providers of the module
+func withModule(m *Module) Option {
+	return func(opts *Options) {
+		opts.module = m
+	}
+}
+
+// installModules registers the providers of the modules and then runs their invocations
+func (x *Dix) installModules(modules []*Module) {
+	type invocation struct {
+		module *Module
+		fn     any
+	}
+
+	var invocations []invocation
+	installed := make(map[*Module]bool)
+
+	var install func(m *Module)
+	install = func(m *Module) {
+		if installed[m] {
+			return
+		}
+		installed[m] = true
+
+		for _, item := range m.items {
+			switch {
+			case item.include != nil:
+				install(item.include)
+			case item.invoke:
+				invocations = append(invocations, invocation{module: m, fn: item.fn})
+			default:
+				x.provideIn(item.fn, providerScope{module: m, private: item.private, conds: item.conds})
+			}
+		}
+	}
+
+	for _, m := range modules {
+		install(m)
+	}
+
+	for _, inv := range invocations {
+		if _, ok := x.isCycle(); ok {
+			panic(errors.New("circular dependency: " + x.cycleReport()))
+		}
+
+		if err := x.inject(inv.fn, withModule(inv.module)).GetErr(); err != nil {
+			panic(errors.WrapKV(err, "module", inv.module.name))
+		}
+	}
+}
+
+// visible reports whether the provider can be injected in the scope of the module
+func (n *providerFn) visible(scope *Module) bool {
+	return !n.private || n.module == scope
+}
+
+// visibleProviders returns the providers of the type which can be injected in the scope of the module
+// and the seq of the hidden ones, x.mu must be held
+func (x *Dix) visibleProviders(typ reflect.Type, scope *Module) ([]*providerFn, map[int]bool) {
+	hidden := make(map[int]bool)
+	nodes := slices.DeleteFunc(slices.Clone(x.providers[typ]), func(n *providerFn) bool {
+		if !n.visible(scope) {
+			hidden[n.seq] = true
+			return true
+		}
+		return false
+	})
+	return nodes, hidden
+}
+
+// visibleObjects returns the objects of the type without the values of the hidden providers
+// and the indexes of the returned values in x.objects, x.mu must be held
+func (x *Dix) visibleObjects(typ reflect.Type, hidden map[int]bool) (map[group][]value, map[group][]int) {
+	objects := make(map[group][]value)
+	indexes := make(map[group][]int)
+	for ns, values := range x.objects[typ] {
+		for i, v := range values {
+			if len(hidden) > 0 && hidden[x.origins[typ][ns][i]] {
+				continue
+			}
+
+			objects[ns] = append(objects[ns], v)
+			indexes[ns] = append(indexes[ns], i)
+		}
+	}
+	return objects, indexes
+}
@@ -0,0 +1,102 @@
+package dixinternal
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestModulePrivate(t *testing.T) {
+	var gotA, gotB string
+	a := NewModule("a",
+		Private(func() *testRedis { return &testRedis{Addr: "a"} }),
+		Provide(func(r *testRedis) *testHandler { return new(testHandler) }),
+		Invoke(func(r *testRedis) { gotA = r.Addr }),
+	)
+	b := NewModule("b",
+		Private(func() *testRedis { return &testRedis{Addr: "b"} }),
+		Invoke(func(r *testRedis) { gotB = r.Addr }),
+	)
+
+	di := New(WithSilentLog(), a, b)
+	if gotA != "a" || gotB != "b" {
+		t.Fatalf("each module should see its own private value, a=%s b=%s", gotA, gotB)
+	}
+
+	if err := di.TryInject(func(*testRedis) {}); err == nil {
+		t.Fatal("the private values should be hidden outside of their modules")
+	}
+
+	// the public provider is built from the private value of its module
+	if err := di.TryInject(func(*testHandler) {}); err != nil {
+		t.Fatal(err)
+	}
+
+	err := func() (err any) {
+		defer func() { err = recover() }()
+		New(WithSilentLog(), a, NewModule("c", Invoke(func(*testRedis) {})))
+		return nil
+	}()
+	if err, ok := err.(error); !ok || !strings.Contains(err.Error(), "provider value not found") {
+		t.Fatalf("another module should not see the private value, err=%v", err)
+	}
+}
+
+func TestModuleInvokeOrder(t *testing.T) {
+	var order []string
+	c := NewModule("c", Invoke(func(*testDB) { order = append(order, "c") }))
+	a := NewModule("a",
+		Invoke(func(*testKafka) { order = append(order, "a1") }),
+		Include(c),
+		Invoke(func(*testKafka) { order = append(order, "a2") }),
+	)
+
+	// the invocations run after every module is installed, so a can use the providers of b
+	b := NewModule("b",
+		Provide(func() *testKafka { order = append(order, "kafka"); return new(testKafka) }),
+		Provide(func() *testDB { return new(testDB) }),
+	)
+
+	New(WithSilentLog(), a, b)
+	if want := []string{"kafka", "a1", "c", "a2"}; !slices.Equal(order, want) {
+		t.Fatalf("order=%v, want %v", order, want)
+	}
+}
+
+func TestModuleInclude(t *testing.T) {
+	var invoked int
+	shared := NewModule("shared",
+		Provide(func() *testDB { return new(testDB) }),
+		Invoke(func(*testDB) { invoked++ }),
+	)
+	a := NewModule("a", Include(shared))
+	b := NewModule("b", Include(shared, shared))
+
+	di := New(WithSilentLog(), a, b, shared)
+	if n := len(di.providers[reflect.TypeOf(new(testDB))]); n != 1 {
+		t.Fatalf("the shared module should be installed once, providers=%d", n)
+	}
+
+	if invoked != 1 {
+		t.Fatalf("the invocation of the shared module should run once, invoked=%d", invoked)
+	}
+}
+
+func TestModuleProvideContainer(t *testing.T) {
+	di := New(WithSilentLog())
+	Provide(di, func() *testRedis { return &testRedis{Addr: "redis"} }, Profile("prod"))
+	if err := di.TryInject(func(*testRedis) {}); err == nil {
+		t.Fatal("the provider is registered with its condition, no profile is active")
+	}
+
+	err := func() (err any) {
+		defer func() { err = recover() }()
+		NewModule("m", Provide(di, func() *testDB { return new(testDB) }))
+		return nil
+	}()
+	if err == nil || !strings.Contains(fmt.Sprint(err), "already registered into a container") {
+		t.Fatalf("a module can not take providers of a container, err=%v", err)
+	}
+}
@@ -0,0 +1,92 @@
+package dixinternal
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"time"
+
+	"github.com/pubgo/funk/stack"
+)
+
+// Observer receives the lifecycle events of a Dix, see WithObserver.
+// The callbacks are invoked synchronously without holding the container lock, so they may call back into the Dix.
+// Embed NopObserver to implement only some of them.
+type Observer interface {
+	// ProviderRegistered is called when Provide accepts the provider
+	ProviderRegistered(p ProviderEvent)
+
+	// ProviderStarted is called before the provider func is called, its inputs are resolved
+	ProviderStarted(p ProviderEvent)
+
+	// ProviderFinished is called after the provider func returns, err is the returned error or the recovered panic
+	ProviderFinished(p ProviderEvent, err error, dur time.Duration)
+
+	// InjectStarted is called when Inject starts, target is the type of the injection parameter
+	InjectStarted(target reflect.Type)
+
+	// InjectFinished is called when Inject ends
+	InjectFinished(target reflect.Type, err error, dur time.Duration)
+
+	// ValueResolved is called for every namespace a dependency value is taken from
+	ValueResolved(typ reflect.Type, ns string)
+}
+
+// ProviderEvent describes the provider of an Observer callback
+type ProviderEvent struct {
+	Func     string
+	Location string
+	Seq      int
+
+	// Output is the result type of the provider func, Inputs are its parameter types
+	Output reflect.Type
+	Inputs []reflect.Type
+}
+
+type NopObserver struct{}
+
+func (NopObserver) ProviderRegistered(ProviderEvent)                     {}
+func (NopObserver) ProviderStarted(ProviderEvent)                        {}
+func (NopObserver) ProviderFinished(ProviderEvent, error, time.Duration) {}
+func (NopObserver) InjectStarted(reflect.Type)                           {}
+func (NopObserver) InjectFinished(reflect.Type, error, time.Duration)    {}
+func (NopObserver) ValueResolved(reflect.Type, string)                   {}
+
+func newProviderEvent(fn reflect.Value, seq int) ProviderEvent {
+	frame := stack.CallerWithFunc(fn)
+	evt := ProviderEvent{
+		Func:     frame.Pkg + "." + frame.Name,
+		Location: fmt.Sprintf("%s:%d", frame.File, frame.Line),
+		Seq:      seq,
+		Output:   fn.Type().Out(0),
+	}
+
+	for i := 0; i < fn.Type().NumIn(); i++ {
+		evt.Inputs = append(evt.Inputs, fn.Type().In(i))
+	}
+	return evt
+}
+
+func (x *Dix) notify(fn func(o Observer)) {
+	for _, o := range x.observers {
+		fn(o)
+	}
+}
+
+// logObserver is the default observer, it logs the provider evaluation at debug level
+type logObserver struct {
+	NopObserver
+	log Logger
+}
+
+func (l logObserver) ProviderStarted(p ProviderEvent) {
+	l.log.Debug(fmt.Sprintf("start eval provider func %s", filepath.Base(p.Func)), "provider", p.Location)
+}
+
+func (l logObserver) ProviderFinished(p ProviderEvent, err error, dur time.Duration) {
+	if err != nil {
+		return
+	}
+
+	l.log.Debug(fmt.Sprintf("eval provider ok, func %s", filepath.Base(p.Func)), "cost", dur.String(), "provider", p.Location)
+}
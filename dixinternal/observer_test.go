@@ -0,0 +1,121 @@
+package dixinternal
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// recordObserver records the events as kind:type
+type recordObserver struct {
+	events []string
+	errs   map[string]error
+	hook   func(p ProviderEvent)
+}
+
+func (o *recordObserver) add(kind string, typ reflect.Type) {
+	o.events = append(o.events, kind+":"+typ.String())
+}
+
+func (o *recordObserver) ProviderRegistered(p ProviderEvent) { o.add("registered", p.Output) }
+
+func (o *recordObserver) ProviderStarted(p ProviderEvent) { o.add("started", p.Output) }
+
+func (o *recordObserver) ProviderFinished(p ProviderEvent, err error, dur time.Duration) {
+	o.add("finished", p.Output)
+	if err != nil {
+		o.errs[p.Output.String()] = err
+	}
+	if o.hook != nil {
+		o.hook(p)
+	}
+}
+
+func (o *recordObserver) InjectStarted(target reflect.Type) { o.add("inject", target) }
+
+func (o *recordObserver) InjectFinished(target reflect.Type, err error, dur time.Duration) {
+	o.add("injected", target)
+}
+
+func (o *recordObserver) ValueResolved(typ reflect.Type, ns string) { o.add("resolved", typ) }
+
+func TestObserverOrder(t *testing.T) {
+	o := &recordObserver{errs: make(map[string]error)}
+	di := New(WithSilentLog(), WithObserver(o))
+	di.Provide(func() *testDB { return new(testDB) })
+	di.Provide(func(*testDB) *testCache { return new(testCache) })
+	di.Inject(func(*testCache) {})
+
+	want := []string{
+		"registered:*dixinternal.testDB",
+		"registered:*dixinternal.testCache",
+		"inject:func(*dixinternal.testCache)",
+		"started:*dixinternal.testDB",
+		"finished:*dixinternal.testDB",
+		"resolved:*dixinternal.testDB",
+		"started:*dixinternal.testCache",
+		"finished:*dixinternal.testCache",
+		"resolved:*dixinternal.testCache",
+		"injected:func(*dixinternal.testCache)",
+	}
+	if got := o.events[len(o.events)-len(want):]; !slices.Equal(got, want) {
+		t.Fatalf("events=\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestObserverProviderError(t *testing.T) {
+	o := &recordObserver{errs: make(map[string]error)}
+	di := New(WithSilentLog(), WithObserver(o))
+	di.Provide(func() (*testDB, error) { return nil, errors.New("dial failed") })
+	di.Provide(func() *testKafka { panic("no broker") })
+
+	if err := di.TryInject(func(*testDB) {}); err == nil {
+		t.Fatal("the provider error should fail the injection")
+	}
+	if err := o.errs["*dixinternal.testDB"]; err == nil || err.Error() != "dial failed" {
+		t.Fatalf("the returned error should be reported, err=%v", err)
+	}
+
+	if err := di.TryInject(func(*testKafka) {}); err == nil {
+		t.Fatal("the provider panic should fail the injection")
+	}
+	if err := o.errs["*dixinternal.testKafka"]; err == nil || !strings.Contains(fmt.Sprint(err), "no broker") {
+		t.Fatalf("the recovered panic should be reported, err=%v", err)
+	}
+}
+
+func TestObserverReentrant(t *testing.T) {
+	o := &recordObserver{errs: make(map[string]error)}
+	di := New(WithSilentLog(), WithObserver(o))
+	di.Provide(func() *testDB { return new(testDB) })
+	di.Provide(func() *testRedis { return &testRedis{Addr: "localhost:6379"} })
+
+	// the callback injects and provides into the container which called it
+	var addr string
+	o.hook = func(p ProviderEvent) {
+		if p.Output == reflect.TypeOf(new(testDB)) {
+			di.Inject(func(r *testRedis) { addr = r.Addr })
+			di.Provide(func() *testKafka { return new(testKafka) })
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		di.Inject(func(*testDB) {})
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("a callback calling back into the container should not deadlock")
+	}
+
+	if addr != "localhost:6379" {
+		t.Fatalf("the callback should be injected, addr=%s", addr)
+	}
+}
@@ -1,10 +1,52 @@
 package dixinternal
 
+import (
+	"flag"
+	"log/slog"
+)
+
 type (
 	Option  func(opts *Options)
 	Options struct {
 		// AllowValuesNull allows result to be nil
 		AllowValuesNull bool
+
+		// FlagSet resolves struct fields tagged with `flag:"name"`
+		FlagSet *flag.FlagSet
+
+		// Parallelism is the max number of providers Build evaluates concurrently
+		Parallelism int
+
+		// RejectCycle makes Provide panic when the provider creates a dependency cycle,
+		// by default the cycle is reported by Inject
+		RejectCycle bool
+
+		// Observers receive the lifecycle events, they are set by New only
+		Observers []Observer
+
+		// Logger is the logger of the container, the package logger by default, it is set by New only
+		Logger Logger
+
+		// LogLevel drops the records below it, nil keeps all records
+		LogLevel *slog.Level
+
+		// UnusedCheck is the action of CheckUnused
+		UnusedCheck UnusedAction
+
+		// Fakes resolves the interface types without providers in the test mode
+		Fakes FakeFactory
+
+		// Profiles are the active profiles of the Profile conditions
+		Profiles []string
+
+		// Plugins are the enabled plugin names by registry, they are installed by New before the modules
+		Plugins map[string][]string
+
+		// Modules are installed by New in order, see NewModule
+		Modules []*Module
+
+		// module is the scope of an injection, the private providers of other modules are hidden from it
+		module *Module
 	}
 )
 
@@ -12,6 +54,18 @@ func (o Options) Merge(opt Options) Options {
 	if o.AllowValuesNull {
 		opt.AllowValuesNull = o.AllowValuesNull
 	}
+
+	if opt.FlagSet == nil {
+		opt.FlagSet = o.FlagSet
+	}
+
+	if o.RejectCycle {
+		opt.RejectCycle = o.RejectCycle
+	}
+
+	if opt.Parallelism == 0 {
+		opt.Parallelism = o.Parallelism
+	}
 	return opt
 }
 
@@ -24,3 +78,56 @@ func WithValuesNull() Option {
 		opts.AllowValuesNull = true
 	}
 }
+
+func WithFlagSet(fs *flag.FlagSet) Option {
+	return func(opts *Options) {
+		opts.FlagSet = fs
+	}
+}
+
+// WithParallelInit lets Build evaluate up to n independent providers concurrently
+func WithParallelInit(n int) Option {
+	return func(opts *Options) {
+		opts.Parallelism = n
+	}
+}
+
+// WithRejectCycle makes Provide panic when the provider creates a dependency cycle
+func WithRejectCycle() Option {
+	return func(opts *Options) {
+		opts.RejectCycle = true
+	}
+}
+
+// WithObserver registers observers of the provider and injection lifecycle, it only takes effect in New
+func WithObserver(observers ...Observer) Option {
+	return func(opts *Options) {
+		opts.Observers = append(opts.Observers, observers...)
+	}
+}
+
+// WithLogger sets the logger of the container, see SlogLogger and NopLogger
+func WithLogger(log Logger) Option {
+	return func(opts *Options) {
+		opts.Logger = log
+	}
+}
+
+// WithLogLevel drops the log records of the container below level
+func WithLogLevel(level slog.Level) Option {
+	return func(opts *Options) {
+		opts.LogLevel = &level
+	}
+}
+
+// WithSilentLog disables the logging of the container
+func WithSilentLog() Option {
+	return WithLogger(NopLogger{})
+}
+
+// WithUnusedCheck sets whether CheckUnused logs or fails on dead providers
+func WithUnusedCheck(action UnusedAction) Option {
+	return func(opts *Options) {
+		opts.UnusedCheck = action
+	}
+}
@@ -0,0 +1,76 @@
+package dixinternal
+
+import (
+	"reflect"
+	"slices"
+)
+
+// replacedType is the state of an output type before Override
+type replacedType struct {
+	providers []*providerFn
+	objects   map[group][]value
+	origins   map[group][]int
+	usage     *typeUsage
+}
+
+// Override registers param as the only provider of its output types until restore is called.
+// The objects of the replaced types are dropped so the next injection evaluates param,
+// objects already built from the replaced values keep them, so override before resolving the dependents.
+func (x *Dix) Override(param any) (restore func()) {
+	seq := x.provideIn(param, providerScope{})
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	replaced := make(map[outputType]replacedType)
+	var fn reflect.Value
+	for typ, providers := range x.providers {
+		if !slices.ContainsFunc(providers, func(n *providerFn) bool { return n.seq == seq }) {
+			continue
+		}
+
+		replaced[typ] = replacedType{
+			providers: slices.DeleteFunc(slices.Clone(providers), func(n *providerFn) bool { return n.seq == seq }),
+			objects:   x.objects[typ],
+			origins:   x.origins[typ],
+			usage:     x.usage[typ],
+		}
+
+		x.providers[typ] = slices.DeleteFunc(slices.Clone(providers), func(n *providerFn) bool { return n.seq != seq })
+		fn = x.providers[typ][0].fn
+		delete(x.objects, typ)
+		delete(x.origins, typ)
+		x.resetUsage(typ)
+	}
+
+	// the same func may be an override before
+	delete(x.initializer, fn)
+	delete(x.initStats, fn)
+	delete(x.disabled, fn)
+
+	return func() {
+		x.mu.Lock()
+		defer x.mu.Unlock()
+
+		for typ, r := range replaced {
+			x.providers[typ] = r.providers
+			delete(x.objects, typ)
+			delete(x.origins, typ)
+			x.resetUsage(typ)
+			if r.objects != nil {
+				x.objects[typ] = r.objects
+				x.origins[typ] = r.origins
+			}
+			if r.usage != nil {
+				x.usage[typ] = r.usage
+			}
+		}
+
+		delete(x.initializer, fn)
+		delete(x.initStats, fn)
+		delete(x.disabled, fn)
+
+		// the override is no longer registered, neither are its edges
+		x.rebuildDepGraph()
+	}
+}
@@ -0,0 +1,49 @@
+package dixinternal
+
+import (
+	"testing"
+)
+
+func TestOverrideRestore(t *testing.T) {
+	di := New(WithSilentLog(), WithRejectCycle())
+	di.Provide(func() *testRedis { return &testRedis{Addr: "redis"} })
+	di.Provide(func() *testKafka { return new(testKafka) })
+
+	restore := di.Override(func(*testCache) *testRedis { return &testRedis{Addr: "fake"} })
+	di.Provide(func() *testCache { return new(testCache) })
+	di.Inject(func(got *testRedis) {
+		if got.Addr != "fake" {
+			t.Fatalf("the override should provide the redis, addr=%s", got.Addr)
+		}
+	})
+
+	restore()
+	di.Inject(func(got *testRedis) {
+		if got.Addr != "redis" {
+			t.Fatalf("the original provider should be restored, addr=%s", got.Addr)
+		}
+	})
+
+	if _, ok := di.isCycle(); ok {
+		t.Fatal("the restore should drop the edges of the override")
+	}
+
+	// the redis -> cache edge of the override is gone, so the cache may depend on the redis
+	di.Provide(func(*testRedis) *testCache { return new(testCache) })
+}
+
+func TestOverrideRestoreCycle(t *testing.T) {
+	di := New(WithSilentLog())
+	di.Provide(func(*testCache) *testDB { return new(testDB) })
+	di.Provide(func() *testCache { return new(testCache) })
+
+	restore := di.Override(func(*testDB) *testCache { return new(testCache) })
+	if _, ok := di.isCycle(); !ok {
+		t.Fatal("the override should create a cycle")
+	}
+
+	restore()
+	if path, ok := di.isCycle(); ok {
+		t.Fatalf("the cycle of the override should be dropped, path=%s", path)
+	}
+}
@@ -0,0 +1,148 @@
+package dixinternal
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/pubgo/funk/errors"
+)
+
+type fieldKind int
+
+const (
+	fieldInput fieldKind = iota
+	fieldStruct
+	fieldValue
+	fieldInvalid
+)
+
+// fieldPlan is the compiled injection of one struct field
+type fieldPlan struct {
+	index int
+	field reflect.StructField
+	kind  fieldKind
+	input *providerInputType
+}
+
+// structPlan is the compiled injection of a struct type
+type structPlan struct {
+	fields []*fieldPlan
+}
+
+// funcPlan is the compiled injection of a func type
+type funcPlan struct {
+	inputs         []*providerInputType
+	hasErrorReturn bool
+	err            error
+}
+
+// methodPlan is the indexes of `InjectMethodPrefix` methods of a pointer type
+type methodPlan struct {
+	methods []int
+}
+
+// loadPlan returns the cached plan of typ, compile is called once per type.
+// The namespace selection of an input is part of the plan, providerInputType.isMap selects all namespaces
+// and the others the default one, but the values of a namespace are not: providers registered or evaluated
+// after the plan is compiled add values to it.
+func loadPlan[T any](x *Dix, typ reflect.Type, compile func(typ reflect.Type) T) T {
+	if plan, ok := x.plans.Load(typ); ok {
+		return plan.(T)
+	}
+
+	plan, _ := x.plans.LoadOrStore(typ, compile(typ))
+	return plan.(T)
+}
+
+func compileStructPlan(tp reflect.Type) *structPlan {
+	plan := &structPlan{}
+	for i := 0; i < tp.NumField(); i++ {
+		field := tp.Field(i)
+		fp := &fieldPlan{index: i, field: field}
+		switch {
+		case isValueField(field):
+			fp.kind = fieldValue
+		case field.Type.Kind() == reflect.Struct:
+			fp.kind = fieldStruct
+		default:
+			fp.input = newInputType(field.Type)
+			if fp.input == nil {
+				fp.kind = fieldInvalid
+			}
+		}
+		plan.fields = append(plan.fields, fp)
+	}
+	return plan
+}
+
+func compileFuncPlan(tp reflect.Type) *funcPlan {
+	plan := &funcPlan{}
+	if tp.NumOut() > 1 {
+		plan.err = errors.New("func output num should <=1")
+		return plan
+	}
+
+	if tp.NumIn() == 0 {
+		plan.err = errors.New("func input num should not be zero")
+		return plan
+	}
+
+	if tp.NumOut() == 1 {
+		// 如果有一个返回值，必须是 error 类型
+		errorType := tp.Out(0)
+		if !errorType.Implements(reflect.TypeOf((*error)(nil)).Elem()) {
+			plan.err = &errors.Err{
+				Msg:    "injectable function can only return error type",
+				Detail: fmt.Sprintf("return_type=%s", errorType.String()),
+			}
+			return plan
+		}
+		plan.hasErrorReturn = true
+	}
+
+	for i := 0; i < tp.NumIn(); i++ {
+		inTyp := tp.In(i)
+		input := newInputType(inTyp)
+		if input == nil {
+			plan.err = &errors.Err{
+				Msg:    "incorrect input type",
+				Detail: fmt.Sprintf("inTyp=%s kind=%s", inTyp, inTyp.Kind()),
+			}
+			return plan
+		}
+		plan.inputs = append(plan.inputs, input)
+	}
+	return plan
+}
+
+func compileMethodPlan(tp reflect.Type) *methodPlan {
+	plan := &methodPlan{}
+	for i := 0; i < tp.NumMethod(); i++ {
+		if strings.HasPrefix(tp.Method(i).Name, InjectMethodPrefix) {
+			plan.methods = append(plan.methods, i)
+		}
+	}
+	return plan
+}
+
+// newInputType returns nil when the type kind can not be injected
+func newInputType(typ reflect.Type) *providerInputType {
+	switch typ.Kind() {
+	case reflect.Interface, reflect.Ptr, reflect.Func:
+		return &providerInputType{typ: typ}
+	case reflect.Struct:
+		return &providerInputType{typ: typ, isStruct: true}
+	case reflect.Map:
+		isList := typ.Elem().Kind() == reflect.Slice
+		elem := typ.Elem()
+		if isList {
+			elem = elem.Elem()
+		}
+		return &providerInputType{typ: elem, isMap: true, isList: isList}
+	case reflect.Slice:
+		return &providerInputType{typ: typ.Elem(), isList: true}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,110 @@
+package dixinternal
+
+import (
+	"testing"
+)
+
+type (
+	benchA struct{}
+	benchB struct{}
+	benchC struct{}
+)
+
+type benchHandler struct {
+	A  *benchA
+	B  *benchB
+	C  *benchC
+	As []*benchA
+	Bs map[string]*benchB
+}
+
+func (h *benchHandler) DixInjectC(c *benchC) {
+	h.C = c
+}
+
+func newBenchDix() *Dix {
+	di := New(WithSilentLog())
+	di.Provide(func() *benchA { return new(benchA) })
+	di.Provide(func() *benchB { return new(benchB) })
+	di.Provide(func(a *benchA, b *benchB) *benchC { return new(benchC) })
+	return di
+}
+
+func benchFunc(a *benchA, b *benchB, c *benchC, as []*benchA, bs map[string]*benchB) {}
+
+func TestPlanCached(t *testing.T) {
+	di := newBenchDix()
+	h := new(benchHandler)
+	if err := di.TryInject(h); err != nil {
+		t.Fatal(err)
+	}
+
+	if h.A == nil || h.B == nil || h.C == nil || len(h.As) != 1 || h.Bs["default"] == nil {
+		t.Fatalf("the handler is not injected, handler=%+v", h)
+	}
+
+	di.Inject(benchFunc)
+	compiled := countPlans(di)
+	if compiled == 0 {
+		t.Fatal("the plans should be cached")
+	}
+
+	di.Inject(new(benchHandler))
+	di.Inject(benchFunc)
+	if n := countPlans(di); n != compiled {
+		t.Fatalf("the later injections should reuse the plans, before=%d after=%d", compiled, n)
+	}
+}
+
+func countPlans(di *Dix) int {
+	var n int
+	di.plans.Range(func(key, value any) bool { n++; return true })
+	return n
+}
+
+func BenchmarkInjectStruct(b *testing.B) {
+	di := newBenchDix()
+	di.Inject(new(benchHandler))
+
+	b.Run("cached", func(b *testing.B) {
+		b.ReportAllocs()
+		for range b.N {
+			di.Inject(new(benchHandler))
+		}
+	})
+
+	b.Run("uncached", func(b *testing.B) {
+		b.ReportAllocs()
+		for range b.N {
+			di.plans.Clear()
+			di.Inject(new(benchHandler))
+		}
+	})
+}
+
+func BenchmarkInjectFunc(b *testing.B) {
+	di := newBenchDix()
+	di.Inject(benchFunc)
+
+	b.Run("cached", func(b *testing.B) {
+		b.ReportAllocs()
+		for range b.N {
+			di.Inject(benchFunc)
+		}
+	})
+
+	b.Run("uncached", func(b *testing.B) {
+		b.ReportAllocs()
+		for range b.N {
+			di.plans.Clear()
+			di.Inject(benchFunc)
+		}
+	})
+}
+
+func BenchmarkInjectNewContainer(b *testing.B) {
+	b.ReportAllocs()
+	for range b.N {
+		newBenchDix().Inject(new(benchHandler))
+	}
+}
@@ -40,25 +40,46 @@ type providerOutputType struct {
 	// isStruct bool
 }
 
+// providerScope is where a provider is registered, every providerFn of the provide call gets it
+type providerScope struct {
+	module  *Module
+	private bool
+	conds   []Condition
+	plugin  *Plugin
+}
+
 type providerFn struct {
 	fn        reflect.Value
 	inputList []*providerInputType
 	output    *providerOutputType
 
 	hasError bool
+
+	// seq is the registration order, the fields of a struct output share one seq
+	seq int
+
+	// module is the module which registered the provider, a private provider is visible only in its module
+	module  *Module
+	private bool
+
+	// conds must all hold when the provider is evaluated, or it provides no value
+	conds []Condition
+
+	// plugin is the registry entry the provider was enabled from, its values are provided in the plugin namespace
+	plugin *Plugin
 }
 
-func (n providerFn) call(in []reflect.Value) (r result.Result[[]reflect.Value]) {
+func (n providerFn) call(log Logger, in []reflect.Value) (r result.Result[[]reflect.Value]) {
 	return result.WrapFn(func() ([]reflect.Value, error) { return n.fn.Call(in), nil }).
 		InspectErr(func(err error) {
-			logger.Err(err).
-				Any("fn_stack", stack.CallerWithFunc(n.fn)).
-				Any("fn_type", n.fn.Type().String()).
-				Any("input", fmt.Sprintf("%v", in)).
-				Any("input_data", reflectValueToString(in)).
-				Any("input_types", reflectTypesToString(n.inputList)).
-				Any("output_type", n.output.typ.String()).
-				Msgf("failed to invoke provider")
+			log.Error("failed to invoke provider",
+				"error", err,
+				"fn_stack", stack.CallerWithFunc(n.fn),
+				"fn_type", n.fn.Type().String(),
+				"input", fmt.Sprintf("%v", in),
+				"input_data", reflectValueToString(in),
+				"input_types", reflectTypesToString(n.inputList),
+				"output_type", n.output.typ.String())
 		})
 }
 
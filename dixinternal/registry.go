@@ -0,0 +1,128 @@
+package dixinternal
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"sync"
+
+	"github.com/pubgo/funk/assert"
+	"github.com/pubgo/funk/errors"
+	"github.com/pubgo/funk/stack"
+)
+
+// Plugin is a provider registered by name in a registry, see Register
+type Plugin struct {
+	Registry string
+	Name     string
+
+	// Location is the source of the provider func
+	Location string
+
+	fn any
+}
+
+func (p *Plugin) String() string { return p.Registry + "/" + p.Name }
+
+var registries = struct {
+	mu      sync.RWMutex
+	plugins map[string]map[string]*Plugin
+}{plugins: make(map[string]map[string]*Plugin)}
+
+// Register adds the provider fn to the registry under name, it is meant to be called from init.
+// fn returns one value, with an optional error, which is provided in the namespace name when the plugin is enabled
+// by WithPlugins, so that the enabled plugins of a registry are injected as map[string]T.
+// A name registered twice in one registry panics with the locations of both funcs.
+func Register(registry, name string, fn any) {
+	assert.If(registry == "" || name == "", "the registry and the plugin name should not be empty")
+
+	fnVal := reflect.ValueOf(fn)
+	assert.If(fnVal.Kind() != reflect.Func || fnVal.IsNil(), "the plugin should be a provider func, plugin=%s/%s", registry, name)
+
+	typ := fnVal.Type()
+	assert.If(typ.NumOut() == 0 || typ.NumOut() > 2, "the plugin func should return a value and an optional error, plugin=%s/%s", registry, name)
+	switch typ.Out(0).Kind() {
+	case reflect.Map, reflect.Slice, reflect.Struct:
+		panic(errors.Errorf("the plugin func should return a single value, plugin=%s/%s type=%s", registry, name, typ.Out(0)))
+	}
+
+	frame := stack.CallerWithFunc(fnVal)
+	p := &Plugin{Registry: registry, Name: name, Location: fmt.Sprintf("%s:%d", frame.File, frame.Line), fn: fn}
+
+	registries.mu.Lock()
+	defer registries.mu.Unlock()
+
+	if registries.plugins[registry] == nil {
+		registries.plugins[registry] = make(map[string]*Plugin)
+	}
+
+	if old := registries.plugins[registry][name]; old != nil {
+		panic(errors.Errorf("the plugin is registered twice, plugin=%s first=%s second=%s", p, old.Location, p.Location))
+	}
+	registries.plugins[registry][name] = p
+}
+
+// Plugins returns the plugins registered in the registry, sorted by name
+func Plugins(registry string) []*Plugin {
+	registries.mu.RLock()
+	defer registries.mu.RUnlock()
+
+	var plugins []*Plugin
+	for _, name := range slices.Sorted(maps.Keys(registries.plugins[registry])) {
+		plugins = append(plugins, registries.plugins[registry][name])
+	}
+	return plugins
+}
+
+// WithPlugins enables the named plugins of the registry, the names usually come from the config.
+// New panics on a name which is not registered.
+func WithPlugins(registry string, names ...string) Option {
+	return func(opts *Options) {
+		if opts.Plugins == nil {
+			opts.Plugins = make(map[string][]string)
+		}
+		opts.Plugins[registry] = append(opts.Plugins[registry], names...)
+	}
+}
+
+// installPlugins provides the enabled plugins, the registries in name order and the plugins in the enabled order
+func (x *Dix) installPlugins(enabled map[string][]string) {
+	for _, registry := range slices.Sorted(maps.Keys(enabled)) {
+		registries.mu.RLock()
+		plugins := maps.Clone(registries.plugins[registry])
+		registries.mu.RUnlock()
+
+		seen := make(map[string]bool)
+		for _, name := range enabled[registry] {
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+
+			p := plugins[name]
+			if p == nil {
+				panic(errors.Errorf("the plugin is not registered, plugin=%s/%s registered=%v",
+					registry, name, slices.Sorted(maps.Keys(plugins))))
+			}
+
+			x.provideIn(p.fn, providerScope{plugin: p})
+		}
+	}
+}
+
+// pluginOutput moves the values of a plugin from the default namespace to the namespace of the plugin name
+func pluginOutput(p *Plugin, output map[outputType]map[group][]value, dropped []DroppedValue) {
+	for _, groups := range output {
+		if values, ok := groups[defaultKey]; ok {
+			delete(groups, defaultKey)
+			groups[p.Name] = append(groups[p.Name], values...)
+		}
+	}
+
+	for i := range dropped {
+		if dropped[i].Namespace == defaultKey {
+			dropped[i].Namespace = p.Name
+		}
+	}
+}
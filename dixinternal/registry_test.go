@@ -0,0 +1,113 @@
+package dixinternal
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+)
+
+type testCodec struct {
+	Name string
+}
+
+const testRegistry = "dixinternal-codec"
+
+func init() {
+	Register(testRegistry, "json", func() *testCodec { return &testCodec{Name: "json"} })
+	Register(testRegistry, "yaml", func() (*testCodec, error) { return &testCodec{Name: "yaml"}, nil })
+	Register(testRegistry, "toml", func() *testCodec { return &testCodec{Name: "toml"} })
+}
+
+func recoverMessage(fn func()) (msg string) {
+	defer func() {
+		if err, ok := recover().(error); ok {
+			msg = err.Error()
+		}
+	}()
+	fn()
+	return ""
+}
+
+func TestRegister(t *testing.T) {
+	var names []string
+	for _, p := range Plugins(testRegistry) {
+		names = append(names, p.Name)
+		if p.Registry != testRegistry || !strings.Contains(p.Location, "registry_test.go") {
+			t.Fatalf("plugin=%s location=%s", p, p.Location)
+		}
+	}
+	if want := []string{"json", "toml", "yaml"}; !slices.Equal(names, want) {
+		t.Fatalf("the plugins should be sorted by name, names=%v", names)
+	}
+
+	msg := recoverMessage(func() { Register(testRegistry, "json", func() *testCodec { return nil }) })
+	if !strings.Contains(msg, "registered twice") || !strings.Contains(msg, "registry_test.go") {
+		t.Fatalf("a name registered twice should panic with the locations, msg=%s", msg)
+	}
+
+	msg = recoverMessage(func() { Register(testRegistry, "all", func() map[string]*testCodec { return nil }) })
+	if !strings.Contains(msg, "single value") {
+		t.Fatalf("a plugin of several values should panic, msg=%s", msg)
+	}
+}
+
+func TestWithPlugins(t *testing.T) {
+	// the options add up and a name enabled twice is installed once
+	di := New(WithSilentLog(), WithPlugins(testRegistry, "yaml"), WithPlugins(testRegistry, "json", "yaml"))
+
+	di.Inject(func(codecs map[string]*testCodec) {
+		if got := slices.Sorted(maps.Keys(codecs)); !slices.Equal(got, []string{"json", "yaml"}) {
+			t.Fatalf("the plugins should be injected by name, names=%v", got)
+		}
+		if codecs["yaml"].Name != "yaml" {
+			t.Fatalf("codec=%+v", codecs["yaml"])
+		}
+	})
+
+	var plugins []string
+	for _, n := range di.Inspect().NodesOf(NodeProvider) {
+		if n.Provider.Plugin != "" {
+			plugins = append(plugins, n.Provider.Plugin)
+			if !strings.HasPrefix(n.Label, "["+n.Provider.Plugin+"] ") {
+				t.Fatalf("the graph should label the registry entry, label=%s", n.Label)
+			}
+		}
+	}
+	if want := []string{testRegistry + "/yaml", testRegistry + "/json"}; !slices.Equal(plugins, want) {
+		t.Fatalf("the plugins should be installed in the enabled order, plugins=%v", plugins)
+	}
+}
+
+func TestWithPluginsConfig(t *testing.T) {
+	var cfg struct {
+		Codecs []string `json:"codecs"`
+	}
+	if err := json.Unmarshal([]byte(`{"codecs": ["toml"]}`), &cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	di := New(WithSilentLog(), WithPlugins(testRegistry, cfg.Codecs...))
+	di.Inject(func(codecs map[string]*testCodec) {
+		if len(codecs) != 1 || codecs["toml"] == nil {
+			t.Fatalf("only the configured plugins should be enabled, codecs=%v", codecs)
+		}
+	})
+
+	if err := di.TryInject(func(*testCodec) {}); err == nil {
+		t.Fatal("a plugin value should be provided in its namespace only")
+	}
+}
+
+func TestWithPluginsUnknown(t *testing.T) {
+	msg := recoverMessage(func() { New(WithSilentLog(), WithPlugins(testRegistry, "json", "xml")) })
+	if !strings.Contains(msg, "the plugin is not registered") {
+		t.Fatalf("an unknown plugin should panic, msg=%s", msg)
+	}
+
+	msg = recoverMessage(func() { New(WithSilentLog(), WithPlugins("dixinternal-missing", "json")) })
+	if !strings.Contains(msg, "the plugin is not registered") {
+		t.Fatalf("a plugin of an unknown registry should panic, msg=%s", msg)
+	}
+}
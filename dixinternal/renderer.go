@@ -3,43 +3,121 @@ package dixinternal
 import (
 	"bytes"
 	"fmt"
-	"reflect"
+	"sort"
+	"strings"
+)
+
+type GraphFormat string
 
-	"github.com/pubgo/funk/stack"
-	"github.com/samber/lo"
+const (
+	FormatDOT      GraphFormat = "dot"
+	FormatMermaid  GraphFormat = "mermaid"
+	FormatPlantUML GraphFormat = "plantuml"
 )
 
+// Renderer renders a graph in a text format, nodes are identified by their names
+type Renderer interface {
+	BeginGraph()
+	EndGraph()
+	BeginSubgraph(name, label string)
+	EndSubgraph()
+	RenderNode(name string, attrs map[string]string)
+	RenderEdge(from, to string, attrs map[string]string)
+	String() string
+}
+
+// NewRenderer returns the renderer of the format, DOT by default
+func NewRenderer(format GraphFormat) Renderer {
+	switch format {
+	case FormatMermaid:
+		return NewMermaidRenderer()
+	case FormatPlantUML:
+		return NewPlantUMLRenderer()
+	default:
+		return NewDotRenderer()
+	}
+}
+
+type (
+	GraphOption  func(opts *GraphOptions)
+	GraphOptions struct {
+		// Format is the text format of the rendered graphs, DOT by default
+		Format GraphFormat
+
+		// Focus limits the graphs to the ancestors or descendants of types and providers, see Ancestors and Descendants
+		Focus []GraphFocus
+
+		ClusterByPackage bool
+		ClusterByModule  bool
+		Highlight        bool
+	}
+)
+
+func Format(format GraphFormat) GraphOption {
+	return func(opts *GraphOptions) {
+		opts.Format = format
+	}
+}
+
 // DotRenderer implements DOT format graph rendering
 type DotRenderer struct {
 	buf    *bytes.Buffer
 	indent string
-	cache  map[string]string
 }
 
 func NewDotRenderer() *DotRenderer {
-	return &DotRenderer{
-		buf:    &bytes.Buffer{},
-		indent: "",
-		cache:  make(map[string]string),
+	return &DotRenderer{buf: &bytes.Buffer{}}
+}
+
+var dotEscape = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// dotQuote quotes a DOT id, only the quote, the backslash and the newline are escaped, DOT does not know the Go escapes
+func dotQuote(s string) string {
+	return `"` + dotEscape.Replace(s) + `"`
+}
+
+// dotAttrs formats the attrs sorted by key
+func dotAttrs(attrs map[string]string) string {
+	quoted := make(map[string]string, len(attrs))
+	for k, v := range attrs {
+		quoted[k] = dotQuote(v)
 	}
+	return formatAttrs(quoted, ",", "%s=%s")
 }
 
 func (d *DotRenderer) writef(format string, args ...interface{}) {
 	_, _ = fmt.Fprintf(d.buf, d.indent+format+"\n", args...)
 }
 
+func (d *DotRenderer) BeginGraph() {
+	d.writef("digraph G {")
+	d.writef(dotStyle)
+}
+
+func (d *DotRenderer) EndGraph() {
+	d.writef("}")
+}
+
 func (d *DotRenderer) RenderNode(name string, attrs map[string]string) {
-	d.writef("%s [label=\"%s\"%s]", name, name, d.formatAttrs(attrs))
+	if len(attrs) == 0 {
+		d.writef("%s [label=%s]", dotQuote(name), dotQuote(name))
+		return
+	}
+	d.writef("%s [label=%s,%s]", dotQuote(name), dotQuote(name), dotAttrs(attrs))
 }
 
 func (d *DotRenderer) RenderEdge(from, to string, attrs map[string]string) {
-	d.writef(`"%s" -> "%s" %s`, from, to, d.formatAttrs(attrs))
+	if len(attrs) == 0 {
+		d.writef("%s -> %s", dotQuote(from), dotQuote(to))
+		return
+	}
+	d.writef("%s -> %s [%s]", dotQuote(from), dotQuote(to), dotAttrs(attrs))
 }
 
 func (d *DotRenderer) BeginSubgraph(name, label string) {
 	d.writef("subgraph %s {", name)
 	d.indent += "\t"
-	d.writef("label=\"%s\"", label)
+	d.writef("label=%s", dotQuote(label))
 }
 
 func (d *DotRenderer) EndSubgraph() {
@@ -51,29 +129,196 @@ func (d *DotRenderer) String() string {
 	return d.buf.String()
 }
 
-func (d *DotRenderer) formatAttrs(attrs map[string]string) string {
+// MermaidRenderer implements Mermaid flowchart rendering
+type MermaidRenderer struct {
+	buf    *bytes.Buffer
+	indent string
+	ids    *nodeIDs
+}
+
+func NewMermaidRenderer() *MermaidRenderer {
+	return &MermaidRenderer{buf: &bytes.Buffer{}, ids: newNodeIDs()}
+}
+
+func (m *MermaidRenderer) writef(format string, args ...interface{}) {
+	_, _ = fmt.Fprintf(m.buf, m.indent+format+"\n", args...)
+}
+
+func (m *MermaidRenderer) BeginGraph() {
+	m.writef("flowchart LR")
+	m.indent += "    "
+}
+
+func (m *MermaidRenderer) EndGraph() {
+	m.indent = ""
+}
+
+// node declares the node with its label on first use
+func (m *MermaidRenderer) node(name string) string {
+	id, ok := m.ids.get(name)
+	if ok {
+		return id
+	}
+	return fmt.Sprintf(`%s["%s"]`, id, mermaidEscape(name))
+}
+
+// mermaidEscape escapes a label with the mermaid entity codes, a quote ends the label and < > are html
+var mermaidEscape = strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;").Replace
+
+func (m *MermaidRenderer) RenderNode(name string, attrs map[string]string) {
+	m.writef("%s", m.node(name))
+
+	// mermaid styles use css names
+	style := make(map[string]string)
+	for k, v := range attrs {
+		switch k {
+		case "fillcolor":
+			style["fill"] = v
+		case "color":
+			style["stroke"] = v
+		}
+	}
+
+	if len(style) > 0 {
+		id, _ := m.ids.get(name)
+		m.writef("style %s %s", id, formatAttrs(style, ",", "%s:%s"))
+	}
+}
+
+func (m *MermaidRenderer) RenderEdge(from, to string, attrs map[string]string) {
+	label := attrs["label"]
+	if label == "" {
+		m.writef("%s --> %s", m.node(from), m.node(to))
+		return
+	}
+	m.writef("%s -->|%s| %s", m.node(from), strings.ReplaceAll(mermaidEscape(label), "|", "#124;"), m.node(to))
+}
+
+func (m *MermaidRenderer) BeginSubgraph(name, label string) {
+	m.writef(`subgraph %s ["%s"]`, name, mermaidEscape(label))
+	m.indent += "    "
+}
+
+func (m *MermaidRenderer) EndSubgraph() {
+	m.indent = m.indent[:len(m.indent)-4]
+	m.writef("end")
+}
+
+func (m *MermaidRenderer) String() string {
+	return m.buf.String()
+}
+
+// PlantUMLRenderer implements PlantUML rendering
+type PlantUMLRenderer struct {
+	buf    *bytes.Buffer
+	indent string
+	ids    *nodeIDs
+}
+
+func NewPlantUMLRenderer() *PlantUMLRenderer {
+	return &PlantUMLRenderer{buf: &bytes.Buffer{}, ids: newNodeIDs()}
+}
+
+func (p *PlantUMLRenderer) writef(format string, args ...interface{}) {
+	_, _ = fmt.Fprintf(p.buf, p.indent+format+"\n", args...)
+}
+
+func (p *PlantUMLRenderer) BeginGraph() {
+	p.writef("@startuml")
+	p.writef("left to right direction")
+}
+
+func (p *PlantUMLRenderer) EndGraph() {
+	p.writef("@enduml")
+}
+
+// node declares the node with its label on first use
+func (p *PlantUMLRenderer) node(name string, attrs map[string]string) string {
+	id, ok := p.ids.get(name)
+	if !ok {
+		color := ""
+		if c := attrs["fillcolor"]; c != "" {
+			color = " " + c
+		}
+		p.writef(`rectangle "%s" as %s%s`, plantUMLEscape(name), id, color)
+	}
+	return id
+}
+
+// plantUMLEscape replaces the quotes which end a PlantUML string, it has no escape for them
+var plantUMLEscape = strings.NewReplacer(`"`, "'").Replace
+
+func (p *PlantUMLRenderer) RenderNode(name string, attrs map[string]string) {
+	p.node(name, attrs)
+}
+
+func (p *PlantUMLRenderer) RenderEdge(from, to string, attrs map[string]string) {
+	from, to = p.node(from, nil), p.node(to, nil)
+	if label := attrs["label"]; label != "" {
+		p.writef("%s --> %s : %s", from, to, label)
+		return
+	}
+	p.writef("%s --> %s", from, to)
+}
+
+func (p *PlantUMLRenderer) BeginSubgraph(name, label string) {
+	p.writef(`package "%s" {`, plantUMLEscape(label))
+	p.indent += "  "
+}
+
+func (p *PlantUMLRenderer) EndSubgraph() {
+	p.indent = p.indent[:len(p.indent)-2]
+	p.writef("}")
+}
+
+func (p *PlantUMLRenderer) String() string {
+	return p.buf.String()
+}
+
+// nodeIDs assigns short ids to node names for the formats which do not allow arbitrary node ids
+type nodeIDs struct {
+	ids map[string]string
+}
+
+func newNodeIDs() *nodeIDs {
+	return &nodeIDs{ids: make(map[string]string)}
+}
+
+// get returns the id of the name and whether it was assigned before
+func (n *nodeIDs) get(name string) (string, bool) {
+	if id, ok := n.ids[name]; ok {
+		return id, true
+	}
+
+	id := fmt.Sprintf("n%d", len(n.ids))
+	n.ids[name] = id
+	return id, false
+}
+
+// formatAttrs formats the attrs sorted by key
+func formatAttrs(attrs map[string]string, sep, format string) string {
 	if len(attrs) == 0 {
 		return ""
 	}
 
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
 	var result bytes.Buffer
-	result.WriteString(" [")
-	first := true
-	for k, v := range attrs {
-		if !first {
-			result.WriteString(",")
+	for i, k := range keys {
+		if i > 0 {
+			result.WriteString(sep)
 		}
-		first = false
-		fmt.Fprintf(&result, "%s=\"%s\"", k, v)
+		fmt.Fprintf(&result, format, k, attrs[k])
 	}
-	result.WriteString("]")
 	return result.String()
 }
 
-func (x *Dix) providerGraphTypes() string {
-	d := NewDotRenderer()
-	d.writef("digraph G {")
-	d.writef(`
+// dotStyle is the layout and style of the provider graphs
+const dotStyle = `
 	// 设置布局引擎
     layout=dot;
 
@@ -105,111 +350,125 @@ func (x *Dix) providerGraphTypes() string {
         color="#888888",
         penwidth=0.5
     ];
-`)
-	d.BeginSubgraph("cluster_providers", "providers")
+`
 
-	for providerOutputType, nodes := range x.providers {
-		if providerOutputType.String() == "" {
+type graphEdge struct {
+	from, to string
+}
+
+// graphNode is a node declared before the edges, to be placed in a cluster or styled
+type graphNode struct {
+	name    string
+	cluster string
+	attrs   map[string]string
+}
+
+// renderEdges renders the nodes and the edges sorted and deduplicated in one subgraph,
+// the nodes of a cluster are rendered in a nested subgraph
+func renderEdges(r Renderer, name, label string, nodes []graphNode, edges []graphEdge) string {
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].from != edges[j].from {
+			return edges[i].from < edges[j].from
+		}
+		return edges[i].to < edges[j].to
+	})
+
+	sort.SliceStable(nodes, func(i, j int) bool {
+		if nodes[i].cluster != nodes[j].cluster {
+			return nodes[i].cluster < nodes[j].cluster
+		}
+		return nodes[i].name < nodes[j].name
+	})
+
+	r.BeginGraph()
+	r.BeginSubgraph(name, label)
+
+	var clusters int
+	for i, n := range nodes {
+		if i > 0 && n.name == nodes[i-1].name {
 			continue
 		}
 
-		for _, n := range nodes {
-			for _, in := range n.inputList {
-				var typesToRender []reflect.Type
-				if in.typ.Kind() == reflect.Struct {
-					typesToRender = lo.Uniq(lo.Map(getProvideAllInputs(in.typ), func(item *providerInputType, index int) reflect.Type { return item.typ }))
-				} else {
-					typesToRender = []reflect.Type{in.typ}
-				}
-
-				for _, t := range typesToRender {
-					if t.String() != "" {
-						d.RenderEdge(t.String(), providerOutputType.String(), nil)
-					}
-				}
-			}
+		if n.cluster != "" && (i == 0 || n.cluster != nodes[i-1].cluster) {
+			r.BeginSubgraph(fmt.Sprintf("%s_%d", name, clusters), n.cluster)
+			clusters++
 		}
-	}
 
-	d.EndSubgraph()
-	d.writef("}")
-	return d.String()
-}
+		r.RenderNode(n.name, n.attrs)
 
-func (x *Dix) providerGraph() string {
-	d := NewDotRenderer()
-	d.writef("digraph G {")
-	d.writef(`
-	// 设置布局引擎
-    layout=dot;
+		if n.cluster != "" && (i == len(nodes)-1 || n.cluster != nodes[i+1].cluster) {
+			r.EndSubgraph()
+		}
+	}
 
-    // 图形整体设置
-    rankdir=LR;          // 左到右布局
-    overlap=false;       // 避免节点重叠
-    splines=true;        // 使用曲线边
-    nodesep=0.5;         // 节点间距
-    ranksep=1.0;         // 层级间距
-    concentrate=true;    // 合并重复边
+	for i, e := range edges {
+		if i > 0 && e == edges[i-1] {
+			continue
+		}
+		r.RenderEdge(e.from, e.to, nil)
+	}
+	r.EndSubgraph()
+	r.EndGraph()
+	return r.String()
+}
 
-    // 节点样式
-    node [
-        shape=box,
-        style=filled,
-        fillcolor="#F9F9F9",
-        color="#666666",
-        fontsize=8,
-        fontname="Arial",
-        width=0.1,
-        height=0.1,
-        fixedsize=false
-    ];
+// graphNodes returns the nodes of the kinds which need a declaration for the options
+func graphNodes(g *GraphModel, opt *GraphOptions, kinds ...NodeKind) []graphNode {
+	var nodes []graphNode
+	for _, kind := range kinds {
+		for _, n := range g.NodesOf(kind) {
+			node := graphNode{name: n.Label}
+			switch {
+			case n.Kind != NodeProvider:
+			case opt.ClusterByModule && n.Provider.Module != "":
+				node.cluster = "module " + n.Provider.Module
+			case opt.ClusterByModule && n.Provider.Plugin != "":
+				node.cluster = "registry " + strings.SplitN(n.Provider.Plugin, "/", 2)[0]
+			case opt.ClusterByPackage:
+				node.cluster = n.Provider.Package
+			}
+			if opt.Highlight {
+				node.attrs = highlight(n)
+			}
 
-    // 边样式
-    edge [
-        arrowhead=vee,
-        arrowsize=0.4,
-        color="#888888",
-        penwidth=0.5
-    ];
-`)
-	d.BeginSubgraph("cluster_providers", "providers")
-
-	for providerOutputType, nodes := range x.providers {
-		for _, n := range nodes {
-			fn := stack.CallerWithFunc(n.fn).Short()
-			d.RenderEdge(fn, providerOutputType.String(), nil)
-			for _, in := range n.inputList {
-				if in.typ.Kind() == reflect.Struct {
-					inTypes := lo.Uniq(lo.Map(getProvideAllInputs(in.typ), func(item *providerInputType, index int) reflect.Type { return item.typ }))
-					for _, inType := range inTypes {
-						d.RenderEdge(inType.String(), fn, nil)
-					}
-				} else {
-					d.RenderEdge(in.typ.String(), fn, nil)
-				}
+			if node.cluster != "" || node.attrs != nil {
+				nodes = append(nodes, node)
 			}
 		}
 	}
+	return nodes
+}
 
-	d.EndSubgraph()
-	d.writef("}")
-	return d.String()
+func providerGraphTypes(g *GraphModel, r Renderer, opt *GraphOptions) string {
+	var edges []graphEdge
+	for _, p := range g.NodesOf(NodeProvider) {
+		for _, in := range g.EdgesTo(p.ID) {
+			for _, out := range g.EdgesFrom(p.ID) {
+				edges = append(edges, graphEdge{g.Node(in.From).Label, g.Node(out.To).Label})
+			}
+		}
+	}
+	return renderEdges(r, "cluster_providers", "providers", graphNodes(g, opt, NodeType), edges)
 }
 
-func (x *Dix) objectGraph() string {
-	d := NewDotRenderer()
-	d.writef("digraph G {")
-	d.BeginSubgraph("cluster_objects", "objects")
+func providerGraph(g *GraphModel, r Renderer, opt *GraphOptions) string {
+	var edges []graphEdge
+	for _, p := range g.NodesOf(NodeProvider) {
+		for _, out := range g.EdgesFrom(p.ID) {
+			edges = append(edges, graphEdge{p.Label, g.Node(out.To).Label})
+		}
 
-	for k, objects := range x.objects {
-		for g, values := range objects {
-			for _, v := range values {
-				d.RenderEdge(k.String(), fmt.Sprintf("%s -> %s", g, v.Type().String()), nil)
-			}
+		for _, in := range g.EdgesTo(p.ID) {
+			edges = append(edges, graphEdge{g.Node(in.From).Label, p.Label})
 		}
 	}
+	return renderEdges(r, "cluster_providers", "providers", graphNodes(g, opt, NodeType, NodeProvider), edges)
+}
 
-	d.EndSubgraph()
-	d.writef("}")
-	return d.String()
+func objectGraph(g *GraphModel, r Renderer) string {
+	var edges []graphEdge
+	for _, obj := range g.NodesOf(NodeObject) {
+		edges = append(edges, graphEdge{g.Node(TypeNodeID(obj.Type)).Label, fmt.Sprintf("%s -> %s", obj.Namespace, obj.Label)})
+	}
+	return renderEdges(r, "cluster_objects", "objects", nil, edges)
 }
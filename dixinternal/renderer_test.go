@@ -0,0 +1,64 @@
+package dixinternal
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+// renderGolden renders names which need escaping: a generic type, a quoted struct tag,
+// a channel direction and a quoted cluster label
+func renderGolden(r Renderer) string {
+	const (
+		box    = "*dixinternal.Box[github.com/pubgo/dix/dixinternal.testDB]"
+		tagged = `struct { Name string "json:\"name\"" }`
+		ch     = "chan<- *dixinternal.testDB"
+		newBox = "dixinternal.NewBox"
+	)
+
+	nodes := []graphNode{
+		{name: newBox, cluster: `module "storage"`},
+		{name: box, attrs: map[string]string{"fillcolor": "#F8D7DA", "color": "#C0392B"}},
+	}
+	edges := []graphEdge{
+		{tagged, newBox},
+		{ch, newBox},
+		{newBox, box},
+		{newBox, box},
+	}
+	return renderEdges(r, "cluster_providers", "providers", nodes, edges)
+}
+
+func TestRendererGolden(t *testing.T) {
+	for _, tt := range []struct {
+		format GraphFormat
+		file   string
+	}{
+		{FormatDOT, "graph.dot"},
+		{FormatMermaid, "graph.mmd"},
+		{FormatPlantUML, "graph.puml"},
+	} {
+		t.Run(string(tt.format), func(t *testing.T) {
+			got := renderGolden(NewRenderer(tt.format))
+
+			path := filepath.Join("testdata", tt.file)
+			if *update {
+				if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got != string(want) {
+				t.Fatalf("%s is out of date, run go test -run TestRendererGolden -update\n%s", path, got)
+			}
+		})
+	}
+}
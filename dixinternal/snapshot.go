@@ -0,0 +1,248 @@
+package dixinternal
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/pubgo/funk/errors"
+)
+
+const snapshotVersion = 1
+
+// GraphSnapshot is the stable form of the provider graph, it has no source lines, timings or registration order,
+// so it only changes when the wiring changes
+type GraphSnapshot struct {
+	Version   int                `json:"version"`
+	Providers []SnapshotProvider `json:"providers"`
+
+	// Namespaces are the namespace keys of every instantiated type
+	Namespaces map[string][]string `json:"namespaces"`
+}
+
+// SnapshotProvider is a provider func, ID is the func name with a #n suffix for the func registered n+1 times.
+// A closure is named by the enclosing func and its outputs, e.g. main.main.func() *main.DB, because the compiler
+// numbers the closures of a func in source order.
+type SnapshotProvider struct {
+	ID      string   `json:"id"`
+	Inputs  []string `json:"inputs"`
+	Outputs []string `json:"outputs"`
+}
+
+// Snapshot returns the stable snapshot of the graph
+func (g *GraphModel) Snapshot() *GraphSnapshot {
+	s := &GraphSnapshot{Version: snapshotVersion, Namespaces: make(map[string][]string)}
+
+	seen := make(map[string]int)
+	for _, n := range g.NodesOf(NodeProvider) {
+		p := SnapshotProvider{Inputs: []string{}, Outputs: []string{}}
+		for _, e := range g.EdgesTo(n.ID) {
+			p.Inputs = append(p.Inputs, g.Node(e.From).Label)
+		}
+		for _, e := range g.EdgesFrom(n.ID) {
+			p.Outputs = append(p.Outputs, g.Node(e.To).Label)
+		}
+		p.Inputs = uniqSorted(p.Inputs)
+		p.Outputs = uniqSorted(p.Outputs)
+
+		name := snapshotFuncName(n.Provider.Func, p.Outputs)
+		p.ID = name
+		if seen[name] > 0 {
+			p.ID = fmt.Sprintf("%s#%d", name, seen[name])
+		}
+		seen[name]++
+		s.Providers = append(s.Providers, p)
+	}
+	sort.Slice(s.Providers, func(i, j int) bool { return s.Providers[i].ID < s.Providers[j].ID })
+
+	for _, n := range g.NodesOf(NodeNamespace) {
+		typ := g.Node(TypeNodeID(n.Type)).Label
+		s.Namespaces[typ] = append(s.Namespaces[typ], n.Namespace)
+	}
+	for typ, keys := range s.Namespaces {
+		s.Namespaces[typ] = uniqSorted(keys)
+	}
+	return s
+}
+
+// closureName matches the suffix the compiler gives a closure, func3 of main.main.func3 or func3.1 of a nested one
+var closureName = regexp.MustCompile(`\.func\d+(\.\d+)*$`)
+
+// snapshotFuncName replaces the closure number of fn, which changes when a closure is added above it, by the outputs
+func snapshotFuncName(fn string, outputs []string) string {
+	loc := closureName.FindStringIndex(fn)
+	if loc == nil {
+		return fn
+	}
+
+	switch len(outputs) {
+	case 0:
+		return fn[:loc[0]] + ".func()"
+	case 1:
+		return fn[:loc[0]] + ".func() " + outputs[0]
+	default:
+		return fn[:loc[0]] + ".func() (" + strings.Join(outputs, ", ") + ")"
+	}
+}
+
+// Snapshot returns the stable snapshot of the provider graph, take it after Build to include the namespaces
+func (x *Dix) Snapshot() *GraphSnapshot {
+	return x.Inspect().Snapshot()
+}
+
+// MarshalIndent encodes the snapshot, the output is byte for byte stable for the same wiring
+func (s *GraphSnapshot) MarshalIndent() ([]byte, error) {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// LoadSnapshot decodes a snapshot written by MarshalIndent
+func LoadSnapshot(data []byte) (*GraphSnapshot, error) {
+	var s GraphSnapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, errors.Wrap(err, "failed to decode graph snapshot")
+	}
+
+	if s.Version != snapshotVersion {
+		return nil, errors.Errorf("unsupported graph snapshot version %d", s.Version)
+	}
+	return &s, nil
+}
+
+// ProviderChange is a provider of both snapshots whose inputs or outputs differ
+type ProviderChange struct {
+	ID             string   `json:"id"`
+	AddedInputs    []string `json:"added_inputs,omitempty"`
+	RemovedInputs  []string `json:"removed_inputs,omitempty"`
+	AddedOutputs   []string `json:"added_outputs,omitempty"`
+	RemovedOutputs []string `json:"removed_outputs,omitempty"`
+}
+
+// NamespaceChange is a type whose namespace keys differ
+type NamespaceChange struct {
+	Type    string   `json:"type"`
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+}
+
+// GraphDiff is the wiring change from snapshot a to snapshot b
+type GraphDiff struct {
+	AddedProviders    []SnapshotProvider `json:"added_providers,omitempty"`
+	RemovedProviders  []SnapshotProvider `json:"removed_providers,omitempty"`
+	ChangedProviders  []ProviderChange   `json:"changed_providers,omitempty"`
+	ChangedNamespaces []NamespaceChange  `json:"changed_namespaces,omitempty"`
+}
+
+func (d *GraphDiff) Empty() bool {
+	return len(d.AddedProviders) == 0 && len(d.RemovedProviders) == 0 &&
+		len(d.ChangedProviders) == 0 && len(d.ChangedNamespaces) == 0
+}
+
+func (d *GraphDiff) String() string {
+	var buf strings.Builder
+	list := func(prefix string, items []string) {
+		for _, item := range items {
+			fmt.Fprintf(&buf, "    %s %s\n", prefix, item)
+		}
+	}
+
+	for _, p := range d.AddedProviders {
+		fmt.Fprintf(&buf, "+ provider %s (%s) -> %s\n", p.ID, strings.Join(p.Inputs, ", "), strings.Join(p.Outputs, ", "))
+	}
+	for _, p := range d.RemovedProviders {
+		fmt.Fprintf(&buf, "- provider %s (%s) -> %s\n", p.ID, strings.Join(p.Inputs, ", "), strings.Join(p.Outputs, ", "))
+	}
+	for _, c := range d.ChangedProviders {
+		fmt.Fprintf(&buf, "~ provider %s\n", c.ID)
+		list("+ input", c.AddedInputs)
+		list("- input", c.RemovedInputs)
+		list("+ output", c.AddedOutputs)
+		list("- output", c.RemovedOutputs)
+	}
+	for _, c := range d.ChangedNamespaces {
+		fmt.Fprintf(&buf, "~ namespaces %s\n", c.Type)
+		list("+", c.Added)
+		list("-", c.Removed)
+	}
+	return buf.String()
+}
+
+// DiffGraphs compares two snapshots, providers are matched by ID
+func DiffGraphs(a, b *GraphSnapshot) *GraphDiff {
+	d := new(GraphDiff)
+
+	before := make(map[string]SnapshotProvider)
+	for _, p := range a.Providers {
+		before[p.ID] = p
+	}
+
+	after := make(map[string]bool)
+	for _, p := range b.Providers {
+		after[p.ID] = true
+		old, ok := before[p.ID]
+		if !ok {
+			d.AddedProviders = append(d.AddedProviders, p)
+			continue
+		}
+
+		c := ProviderChange{ID: p.ID}
+		c.AddedInputs, c.RemovedInputs = diffStrings(old.Inputs, p.Inputs)
+		c.AddedOutputs, c.RemovedOutputs = diffStrings(old.Outputs, p.Outputs)
+		if len(c.AddedInputs)+len(c.RemovedInputs)+len(c.AddedOutputs)+len(c.RemovedOutputs) > 0 {
+			d.ChangedProviders = append(d.ChangedProviders, c)
+		}
+	}
+
+	for _, p := range a.Providers {
+		if !after[p.ID] {
+			d.RemovedProviders = append(d.RemovedProviders, p)
+		}
+	}
+
+	var types []string
+	for typ := range a.Namespaces {
+		types = append(types, typ)
+	}
+	for typ := range b.Namespaces {
+		if _, ok := a.Namespaces[typ]; !ok {
+			types = append(types, typ)
+		}
+	}
+	sort.Strings(types)
+
+	for _, typ := range types {
+		added, removed := diffStrings(a.Namespaces[typ], b.Namespaces[typ])
+		if len(added)+len(removed) > 0 {
+			d.ChangedNamespaces = append(d.ChangedNamespaces, NamespaceChange{Type: typ, Added: added, Removed: removed})
+		}
+	}
+	return d
+}
+
+// diffStrings returns the items only in b and the items only in a, sorted
+func diffStrings(a, b []string) (added, removed []string) {
+	for _, s := range b {
+		if !slices.Contains(a, s) {
+			added = append(added, s)
+		}
+	}
+	for _, s := range a {
+		if !slices.Contains(b, s) {
+			removed = append(removed, s)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return
+}
+
+func uniqSorted(list []string) []string {
+	sort.Strings(list)
+	return slices.Compact(list)
+}
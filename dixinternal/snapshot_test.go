@@ -0,0 +1,55 @@
+package dixinternal
+
+import (
+	"testing"
+)
+
+// wireSnapshot registers the closures, the kafka closure comes first in the source.
+// It is not inlined, the closures of an inlined func are named after the caller.
+//
+//go:noinline
+func wireSnapshot(extra bool) *Dix {
+	di := New(WithSilentLog())
+	if extra {
+		di.Provide(func() *testKafka { return new(testKafka) })
+	}
+	di.Provide(func() *testDB { return new(testDB) })
+	di.Provide(func(*testDB) *testCache { return new(testCache) })
+	return di
+}
+
+func TestSnapshotClosureID(t *testing.T) {
+	ids := make(map[string]bool)
+	for _, p := range wireSnapshot(false).Snapshot().Providers {
+		ids[p.ID] = true
+	}
+
+	const db = "github.com/pubgo/dix/dixinternal.wireSnapshot.func() *dixinternal.testDB"
+	if !ids[db] {
+		t.Fatalf("the closure should be named by its output, ids=%v", ids)
+	}
+
+	diff := DiffGraphs(wireSnapshot(false).Snapshot(), wireSnapshot(true).Snapshot())
+	if len(diff.AddedProviders) != 1 || len(diff.RemovedProviders) != 0 || len(diff.ChangedProviders) != 0 {
+		t.Fatalf("only the kafka provider should be added, diff=%+v", diff)
+	}
+}
+
+func TestSnapshotFuncName(t *testing.T) {
+	cases := []struct {
+		fn      string
+		outputs []string
+		want    string
+	}{
+		{"main.NewDB", []string{"*main.DB"}, "main.NewDB"},
+		{"main.main.func3", []string{"*main.DB"}, "main.main.func() *main.DB"},
+		{"main.main.func3.1", []string{"*main.A", "*main.B"}, "main.main.func() (*main.A, *main.B)"},
+		{"main.init.func1", nil, "main.init.func()"},
+	}
+
+	for _, c := range cases {
+		if got := snapshotFuncName(c.fn, c.outputs); got != c.want {
+			t.Errorf("snapshotFuncName(%q) = %q, want %q", c.fn, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,260 @@
+package dixinternal
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"runtime"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pubgo/funk/stack"
+)
+
+// providerStat records the evaluations of a provider func
+type providerStat struct {
+	count     int
+	startedAt time.Time
+	cost      time.Duration
+	err       error
+	goroutine uint64
+
+	// dropped are the nil values handleOutput removed from the output
+	dropped []DroppedValue
+}
+
+func (x *Dix) recordStat(fn reflect.Value, gid uint64, startedAt time.Time, cost time.Duration, err error) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	x.setStat(fn, gid, startedAt, cost, err)
+}
+
+// setStat records the last evaluation of fn, x.mu must be held,
+// gid is taken by the caller before locking since goroutineID formats the stack
+func (x *Dix) setStat(fn reflect.Value, gid uint64, startedAt time.Time, cost time.Duration, err error) {
+	stat := x.initStats[fn]
+	if stat == nil {
+		stat = new(providerStat)
+		x.initStats[fn] = stat
+	}
+
+	stat.count++
+	stat.startedAt = startedAt
+	stat.cost = cost
+	stat.err = err
+	stat.goroutine = gid
+}
+
+// goroutineID parses the id of the current goroutine from its stack header, "goroutine 18 [running]:"
+func goroutineID() uint64 {
+	var buf [64]byte
+	n := runtime.Stack(buf[:], false)
+	fields := strings.Fields(string(buf[:n]))
+	if len(fields) < 2 {
+		return 0
+	}
+
+	id, _ := strconv.ParseUint(fields[1], 10, 64)
+	return id
+}
+
+// ProviderStat is the initialization metric of an evaluated provider
+type ProviderStat struct {
+	Func     string   `json:"func"`
+	Location string   `json:"location"`
+	Seq      int      `json:"seq"`
+	Outputs  []string `json:"outputs"`
+
+	// Count is the number of evaluations, a provider whose call panicked is evaluated again by the next injection
+	Count     int           `json:"count"`
+	StartedAt time.Time     `json:"started_at"`
+	Duration  time.Duration `json:"duration"`
+	Error     string        `json:"error,omitempty"`
+
+	// Goroutine is the id of the goroutine which triggered the initialization
+	Goroutine uint64 `json:"goroutine"`
+}
+
+// BootReport explains the startup latency of the evaluated providers
+type BootReport struct {
+	// Total is the sum of the provider durations
+	Total time.Duration `json:"total"`
+
+	// Wall is the time from the first provider start to the last provider end
+	Wall time.Duration `json:"wall"`
+
+	// CriticalPath is the most expensive dependency chain, from the first provider to the last,
+	// no concurrency can make the startup faster than CriticalPathDuration
+	CriticalPath         []ProviderStat `json:"critical_path"`
+	CriticalPathDuration time.Duration  `json:"critical_path_duration"`
+
+	// Slowest are the providers sorted by duration, the slowest first
+	Slowest []ProviderStat `json:"slowest"`
+}
+
+func (r *BootReport) String() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "providers=%d total=%s wall=%s critical_path=%s\n", len(r.Slowest), r.Total, r.Wall, r.CriticalPathDuration)
+
+	w := tabwriter.NewWriter(&buf, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "critical path\tduration\tlocation")
+	for _, s := range r.CriticalPath {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", s.Func, s.Duration, s.Location)
+	}
+
+	fmt.Fprintln(w, "\nslowest\tduration\tshare")
+	for _, s := range r.Slowest {
+		var share float64
+		if r.Total > 0 {
+			share = float64(s.Duration) / float64(r.Total) * 100
+		}
+		fmt.Fprintf(w, "%s\t%s\t%.1f%%\n", s.Func, s.Duration, share)
+	}
+	_ = w.Flush()
+	return buf.String()
+}
+
+// statProvider is an evaluated provider func with the funcs it depends on
+type statProvider struct {
+	stat ProviderStat
+	deps []reflect.Value
+}
+
+// statProviders groups the evaluated providers by func, x.mu must be held
+func (x *Dix) statProviders() (map[reflect.Value]*statProvider, []reflect.Value) {
+	nodes := make(map[reflect.Value]*statProvider)
+	var order []reflect.Value
+	for outTyp, providers := range x.providers {
+		for _, n := range providers {
+			stat := x.initStats[n.fn]
+			if stat == nil {
+				continue
+			}
+
+			node := nodes[n.fn]
+			if node == nil {
+				frame := stack.CallerWithFunc(n.fn)
+				node = &statProvider{stat: ProviderStat{
+					Func:      frame.Pkg + "." + frame.Name,
+					Location:  fmt.Sprintf("%s:%d", frame.File, frame.Line),
+					Seq:       n.seq,
+					Count:     stat.count,
+					StartedAt: stat.startedAt,
+					Duration:  stat.cost,
+					Goroutine: stat.goroutine,
+				}}
+				if stat.err != nil {
+					node.stat.Error = stat.err.Error()
+				}
+
+				nodes[n.fn] = node
+				order = append(order, n.fn)
+
+				for _, in := range n.inputList {
+					for _, input := range getProvideAllInputs(x.log, in.typ) {
+						for _, dep := range x.providers[input.typ] {
+							if dep.fn != n.fn && !slices.Contains(node.deps, dep.fn) {
+								node.deps = append(node.deps, dep.fn)
+							}
+						}
+					}
+				}
+			}
+			node.stat.Outputs = append(node.stat.Outputs, outTyp.String())
+		}
+	}
+
+	for _, node := range nodes {
+		sort.Strings(node.stat.Outputs)
+		node.deps = slices.DeleteFunc(node.deps, func(fn reflect.Value) bool { return nodes[fn] == nil })
+		sort.Slice(node.deps, func(i, j int) bool { return nodes[node.deps[i]].stat.Seq < nodes[node.deps[j]].stat.Seq })
+	}
+	sort.Slice(order, func(i, j int) bool { return nodes[order[i]].stat.Seq < nodes[order[j]].stat.Seq })
+	return nodes, order
+}
+
+// Stats returns the initialization metrics of the evaluated providers in registration order
+func (x *Dix) Stats() []ProviderStat {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	nodes, order := x.statProviders()
+	stats := make([]ProviderStat, 0, len(order))
+	for _, fn := range order {
+		stats = append(stats, nodes[fn].stat)
+	}
+	return stats
+}
+
+// BootReport computes the critical path through the evaluated providers, see BootReport
+func (x *Dix) BootReport() *BootReport {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	nodes, order := x.statProviders()
+	report := &BootReport{}
+
+	var first, last time.Time
+	for _, fn := range order {
+		s := nodes[fn].stat
+		report.Total += s.Duration
+		report.Slowest = append(report.Slowest, s)
+		if first.IsZero() || s.StartedAt.Before(first) {
+			first = s.StartedAt
+		}
+		if end := s.StartedAt.Add(s.Duration); end.After(last) {
+			last = end
+		}
+	}
+	report.Wall = last.Sub(first)
+	sort.SliceStable(report.Slowest, func(i, j int) bool { return report.Slowest[i].Duration > report.Slowest[j].Duration })
+
+	// dist is the duration of the most expensive chain ending at the provider, next is the dependency it comes from
+	dist := make(map[reflect.Value]time.Duration)
+	next := make(map[reflect.Value]reflect.Value)
+	visiting := make(map[reflect.Value]bool)
+	var longest func(fn reflect.Value) time.Duration
+	longest = func(fn reflect.Value) time.Duration {
+		if d, ok := dist[fn]; ok {
+			return d
+		}
+
+		node := nodes[fn]
+		if node == nil || visiting[fn] {
+			return 0
+		}
+		visiting[fn] = true
+		defer delete(visiting, fn)
+
+		// deps are in registration order, the earliest provider wins a tie
+		var best time.Duration
+		for _, dep := range node.deps {
+			if d := longest(dep); d > best || !next[fn].IsValid() {
+				best = d
+				next[fn] = dep
+			}
+		}
+
+		dist[fn] = best + node.stat.Duration
+		return dist[fn]
+	}
+
+	var end reflect.Value
+	for _, fn := range order {
+		if d := longest(fn); !end.IsValid() || d > report.CriticalPathDuration {
+			end = fn
+			report.CriticalPathDuration = d
+		}
+	}
+
+	for fn, ok := end, end.IsValid(); ok; fn, ok = next[fn] {
+		report.CriticalPath = append(report.CriticalPath, nodes[fn].stat)
+	}
+	slices.Reverse(report.CriticalPath)
+	return report
+}
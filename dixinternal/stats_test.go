@@ -0,0 +1,96 @@
+package dixinternal
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+)
+
+type (
+	bootA struct{}
+	bootB struct{}
+	bootC struct{}
+	bootD struct{}
+)
+
+// newBootDiamond evaluates the diamond A -> B, A -> C, B+C -> D
+func newBootDiamond(t *testing.T) *Dix {
+	di := New(WithSilentLog())
+	di.Provide(func() *bootA { return new(bootA) })
+	di.Provide(func(*bootA) *bootB { return new(bootB) })
+	di.Provide(func(*bootA) *bootC { return new(bootC) })
+	di.Provide(func(*bootB, *bootC) *bootD { return new(bootD) })
+	if err := di.TryInject(func(*bootD) {}); err != nil {
+		t.Fatal(err)
+	}
+	return di
+}
+
+// setCost replaces the measured evaluation of the provider of T with a fixed one
+func setCost[T any](di *Dix, start, cost time.Duration) {
+	di.mu.Lock()
+	defer di.mu.Unlock()
+
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, n := range di.providers[reflect.TypeFor[T]()] {
+		di.initStats[n.fn].startedAt = base.Add(start)
+		di.initStats[n.fn].cost = cost
+	}
+}
+
+func criticalPath(r *BootReport) []string {
+	var path []string
+	for _, s := range r.CriticalPath {
+		path = append(path, s.Outputs...)
+	}
+	return path
+}
+
+func TestBootReport(t *testing.T) {
+	ms := time.Millisecond
+
+	t.Run("critical path", func(t *testing.T) {
+		di := newBootDiamond(t)
+		setCost[*bootA](di, 0, 10*ms)
+		setCost[*bootB](di, 10*ms, 20*ms)
+		setCost[*bootC](di, 10*ms, 40*ms)
+		setCost[*bootD](di, 50*ms, 5*ms)
+
+		r := di.BootReport()
+		want := []string{"*dixinternal.bootA", "*dixinternal.bootC", "*dixinternal.bootD"}
+		if got := criticalPath(r); !slices.Equal(got, want) {
+			t.Fatalf("critical path=%v, want %v", got, want)
+		}
+		if r.CriticalPathDuration != 55*ms {
+			t.Fatalf("critical path duration=%s", r.CriticalPathDuration)
+		}
+		if r.Total != 75*ms || r.Wall != 55*ms {
+			t.Fatalf("total=%s wall=%s", r.Total, r.Wall)
+		}
+		if r.Slowest[0].Outputs[0] != "*dixinternal.bootC" {
+			t.Fatalf("slowest=%s", r.Slowest[0].Outputs)
+		}
+	})
+
+	t.Run("tie", func(t *testing.T) {
+		di := newBootDiamond(t)
+		setCost[*bootA](di, 0, 10*ms)
+		setCost[*bootB](di, 10*ms, 30*ms)
+		setCost[*bootC](di, 10*ms, 30*ms)
+		setCost[*bootD](di, 40*ms, 5*ms)
+
+		// the earliest registered dependency wins a tie, and keeps its place among the slowest
+		r := di.BootReport()
+		want := []string{"*dixinternal.bootA", "*dixinternal.bootB", "*dixinternal.bootD"}
+		if got := criticalPath(r); !slices.Equal(got, want) {
+			t.Fatalf("critical path=%v, want %v", got, want)
+		}
+		if r.CriticalPathDuration != 45*ms {
+			t.Fatalf("critical path duration=%s", r.CriticalPathDuration)
+		}
+		if r.Slowest[0].Outputs[0] != "*dixinternal.bootB" || r.Slowest[1].Outputs[0] != "*dixinternal.bootC" {
+			t.Fatalf("slowest=%v", r.Slowest)
+		}
+	})
+}
@@ -0,0 +1,242 @@
+package dixinternal
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pubgo/funk/errors"
+	"github.com/pubgo/funk/stack"
+)
+
+type UnusedAction int
+
+const (
+	// UnusedIgnore makes CheckUnused do nothing
+	UnusedIgnore UnusedAction = iota
+
+	// UnusedLog makes CheckUnused log every dead provider and unconsumed object
+	UnusedLog
+
+	// UnusedFail makes CheckUnused return an error when there are dead providers
+	UnusedFail
+)
+
+// UnusedProvider is a provider which was never evaluated.
+// A provider which was evaluated but whose values nobody injects is not reported here,
+// its values are reported as UnusedObject instead.
+// Requested is set when one of its output types was requested, e.g. the provider was registered after the injection.
+type UnusedProvider struct {
+	Func      string   `json:"func"`
+	Location  string   `json:"location"`
+	Seq       int      `json:"seq"`
+	Outputs   []string `json:"outputs"`
+	Requested bool     `json:"requested"`
+}
+
+// UnusedObject is an instantiated object which was never injected,
+// e.g. a value of a type nobody requests or a default namespace value shadowed by a later one
+type UnusedObject struct {
+	Type      string `json:"type"`
+	Namespace string `json:"namespace"`
+	Index     int    `json:"index"`
+	Value     string `json:"value"`
+}
+
+type UnusedReport struct {
+	Providers []UnusedProvider `json:"providers"`
+	Objects   []UnusedObject   `json:"objects"`
+}
+
+func (r *UnusedReport) Empty() bool {
+	return len(r.Providers) == 0 && len(r.Objects) == 0
+}
+
+func (r *UnusedReport) String() string {
+	var buf strings.Builder
+	for _, p := range r.Providers {
+		fmt.Fprintf(&buf, "unused provider %s (%s) outputs=%s requested=%v\n", p.Func, p.Location, strings.Join(p.Outputs, ","), p.Requested)
+	}
+	for _, o := range r.Objects {
+		fmt.Fprintf(&buf, "unused object %s namespace=%s index=%d value=%s\n", o.Type, o.Namespace, o.Index, o.Value)
+	}
+	return buf.String()
+}
+
+// typeUsage records which objects of a requested type were injected,
+// the entry is created once under x.mu, the marks are lock free so a cached resolution only takes the read lock
+type typeUsage struct {
+	consumed sync.Map
+}
+
+type consumedKey struct {
+	ns  group
+	idx int
+}
+
+// requestType returns the usage of typ, creating it on the first request
+func (x *Dix) requestType(typ reflect.Type) *typeUsage {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	if x.usage[typ] == nil {
+		x.usage[typ] = new(typeUsage)
+	}
+	return x.usage[typ]
+}
+
+// resetUsage forgets the injected objects of typ but keeps it requested, x.mu must be held
+func (x *Dix) resetUsage(typ reflect.Type) {
+	if x.usage[typ] != nil {
+		x.usage[typ] = new(typeUsage)
+	}
+}
+
+// markConsumed records the values getValue injects by their indexes in x.objects
+func (u *typeUsage) markConsumed(indexes map[group][]int, isMap, isList bool) {
+	mark := func(ns group, idx []int, all bool) {
+		if len(idx) == 0 {
+			return
+		}
+
+		if !all {
+			idx = idx[len(idx)-1:]
+		}
+
+		for _, i := range idx {
+			key := consumedKey{ns: ns, idx: i}
+			if _, ok := u.consumed.Load(key); !ok {
+				u.consumed.Store(key, true)
+			}
+		}
+	}
+
+	switch {
+	case isMap:
+		for ns, idx := range indexes {
+			mark(ns, idx, isList)
+		}
+	case isList:
+		mark(defaultKey, indexes[defaultKey], true)
+	default:
+		mark(defaultKey, indexes[defaultKey], false)
+	}
+}
+
+func (u *typeUsage) isConsumed(ns group, idx int) bool {
+	if u == nil {
+		return false
+	}
+
+	_, ok := u.consumed.Load(consumedKey{ns: ns, idx: idx})
+	return ok
+}
+
+func (u *typeUsage) clone() *typeUsage {
+	c := new(typeUsage)
+	u.consumed.Range(func(key, val any) bool {
+		c.consumed.Store(key, val)
+		return true
+	})
+	return c
+}
+
+// Unused reports the providers which were never evaluated and the objects which were never injected,
+// call it after the application is wired
+func (x *Dix) Unused() *UnusedReport {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	report := new(UnusedReport)
+	selfTyp := reflect.TypeOf(x)
+
+	byFn := make(map[reflect.Value]*UnusedProvider)
+	for outTyp, providers := range x.providers {
+		for _, n := range providers {
+			if n.isSelf() || x.initializer[n.fn] {
+				continue
+			}
+
+			p := byFn[n.fn]
+			if p == nil {
+				frame := stack.CallerWithFunc(n.fn)
+				p = &UnusedProvider{
+					Func:     frame.Pkg + "." + frame.Name,
+					Location: fmt.Sprintf("%s:%d", frame.File, frame.Line),
+					Seq:      n.seq,
+				}
+				byFn[n.fn] = p
+			}
+			p.Outputs = append(p.Outputs, outTyp.String())
+			p.Requested = p.Requested || x.usage[outTyp] != nil
+		}
+	}
+
+	for _, p := range byFn {
+		sort.Strings(p.Outputs)
+		report.Providers = append(report.Providers, *p)
+	}
+	sort.Slice(report.Providers, func(i, j int) bool { return report.Providers[i].Seq < report.Providers[j].Seq })
+
+	for typ, objects := range x.objects {
+		if typ == selfTyp {
+			continue
+		}
+
+		for ns, values := range objects {
+			for i, v := range values {
+				if x.usage[typ].isConsumed(ns, i) {
+					continue
+				}
+
+				report.Objects = append(report.Objects, UnusedObject{Type: typ.String(), Namespace: ns, Index: i, Value: v.Type().String()})
+			}
+		}
+	}
+	sort.Slice(report.Objects, func(i, j int) bool {
+		a, b := report.Objects[i], report.Objects[j]
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Index < b.Index
+	})
+	return report
+}
+
+// CheckUnused logs or fails on the Unused report according to WithUnusedCheck
+func (x *Dix) CheckUnused() error {
+	if x.option.UnusedCheck == UnusedIgnore {
+		return nil
+	}
+
+	report := x.Unused()
+	if report.Empty() {
+		return nil
+	}
+
+	for _, p := range report.Providers {
+		x.log.Warn("unused provider",
+			"provider", p.Func,
+			"location", p.Location,
+			"outputs", strings.Join(p.Outputs, ","),
+			"requested", p.Requested)
+	}
+
+	for _, o := range report.Objects {
+		x.log.Warn("unused object",
+			"type", o.Type,
+			"namespace", o.Namespace,
+			"index", o.Index,
+			"value", o.Value)
+	}
+
+	if x.option.UnusedCheck == UnusedFail && len(report.Providers) > 0 {
+		return errors.Errorf("found %d unused providers\n%s", len(report.Providers), report)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package dixinternal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUnusedProvider(t *testing.T) {
+	di := New(WithSilentLog())
+	di.Provide(func() *testDB { return new(testDB) })
+	di.Provide(func() *testKafka { return new(testKafka) })
+	di.Inject(func(*testDB) {})
+
+	report := di.Unused()
+	if len(report.Providers) != 1 || report.Providers[0].Outputs[0] != "*dixinternal.testKafka" {
+		t.Fatalf("the kafka provider was never evaluated, report=%s", report)
+	}
+
+	if report.Providers[0].Requested {
+		t.Fatal("nobody requested the kafka type")
+	}
+
+	if len(report.Objects) != 0 {
+		t.Fatalf("the db object is injected, report=%s", report)
+	}
+}
+
+func TestUnusedEvaluatedProvider(t *testing.T) {
+	di := New(WithSilentLog())
+	di.Provide(func() testPair { return testPair{DB: new(testDB), Cache: new(testCache)} })
+	di.Inject(func(*testDB) {})
+
+	// the provider is evaluated for the db, so only the cache it returned is unused
+	report := di.Unused()
+	if len(report.Providers) != 0 {
+		t.Fatalf("the provider was evaluated, report=%s", report)
+	}
+
+	if len(report.Objects) != 1 || report.Objects[0].Type != "*dixinternal.testCache" {
+		t.Fatalf("the cache should be an unused object, report=%s", report)
+	}
+}
+
+func TestUnusedRequestedProvider(t *testing.T) {
+	di := New(WithSilentLog())
+	di.Provide(func() *testDB { return new(testDB) })
+	di.Inject(func(*testDB) {})
+
+	// registered after the db was injected
+	di.Provide(func() *testDB { return new(testDB) })
+
+	report := di.Unused()
+	if len(report.Providers) != 1 || !report.Providers[0].Requested {
+		t.Fatalf("the late provider should be unused and requested, report=%s", report)
+	}
+}
+
+func TestUnusedHiddenObjects(t *testing.T) {
+	di := New(WithSilentLog(), NewModule("cache",
+		Private(func() *testRedis { return &testRedis{Addr: "private"} }),
+		Invoke(func(*testRedis) {}),
+	))
+	di.Provide(func() *testRedis { return &testRedis{Addr: "public"} })
+
+	// the private redis comes first in the objects and is hidden here
+	di.Inject(func(r *testRedis) {
+		if r.Addr != "public" {
+			t.Fatalf("the private redis should be hidden, addr=%s", r.Addr)
+		}
+	})
+
+	if report := di.Unused(); len(report.Objects) != 0 {
+		t.Fatalf("both redis values are injected, report=%s", report)
+	}
+}
+
+func TestUnusedCachedReadLock(t *testing.T) {
+	di := New(WithSilentLog())
+	di.Provide(func() *testRedis { return &testRedis{Addr: "redis"} })
+	di.Inject(func(*testRedis) {})
+
+	// a cached resolution records the consumption without the write lock
+	di.mu.RLock()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		di.Inject(func(*testRedis) {})
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the cached resolution should only take the read lock")
+	}
+	di.mu.RUnlock()
+
+	if report := di.Unused(); len(report.Objects) != 0 {
+		t.Fatalf("the redis object is injected, report=%s", report)
+	}
+}
@@ -3,7 +3,6 @@ package dixinternal
 import (
 	"fmt"
 	"reflect"
-	"slices"
 	"strings"
 )
 
@@ -38,10 +37,17 @@ func reflectValueToString(values []reflect.Value) []string {
 	return data
 }
 
-func handleOutput(outType outputType, providerOutTyp reflect.Value) map[outputType]map[group][]value {
+// handleOutput groups the provider output by type and namespace, nil values are dropped and reported
+func handleOutput(outType outputType, providerOutTyp reflect.Value) (map[outputType]map[group][]value, []DroppedValue) {
 	rr := make(map[outputType]map[group][]value)
+	var dropped []DroppedValue
+	drop := func(typ reflect.Type, ns, reason string) {
+		dropped = append(dropped, DroppedValue{Type: typ.String(), Namespace: ns, Reason: reason})
+	}
+
 	if !providerOutTyp.IsValid() || providerOutTyp.IsZero() {
-		return rr
+		drop(outType, defaultKey, "provider returned a zero value")
+		return rr, dropped
 	}
 
 	switch providerOutTyp.Kind() {
@@ -64,6 +70,7 @@ func handleOutput(outType outputType, providerOutTyp reflect.Value) map[outputTy
 
 			val := providerOutTyp.MapIndex(k)
 			if !val.IsValid() || val.IsNil() {
+				drop(outType, mapK, "nil map value")
 				continue
 			}
 
@@ -71,6 +78,7 @@ func handleOutput(outType outputType, providerOutTyp reflect.Value) map[outputTy
 				for i := 0; i < val.Len(); i++ {
 					vv := val.Index(i)
 					if !vv.IsValid() || vv.IsNil() {
+						drop(outType, mapK, fmt.Sprintf("nil list element %d", i))
 						continue
 					}
 
@@ -89,6 +97,7 @@ func handleOutput(outType outputType, providerOutTyp reflect.Value) map[outputTy
 		for i := 0; i < providerOutTyp.Len(); i++ {
 			val := providerOutTyp.Index(i)
 			if !val.IsValid() || val.IsNil() {
+				drop(outType, defaultKey, fmt.Sprintf("nil list element %d", i))
 				continue
 			}
 
@@ -96,7 +105,9 @@ func handleOutput(outType outputType, providerOutTyp reflect.Value) map[outputTy
 		}
 	case reflect.Struct:
 		for i := 0; i < providerOutTyp.NumField(); i++ {
-			for typ, vv := range handleOutput(providerOutTyp.Field(i).Type(), providerOutTyp.Field(i)) {
+			fieldOut, fieldDropped := handleOutput(providerOutTyp.Field(i).Type(), providerOutTyp.Field(i))
+			dropped = append(dropped, fieldDropped...)
+			for typ, vv := range fieldOut {
 				if rr[typ] == nil {
 					rr[typ] = vv
 				} else {
@@ -115,98 +126,73 @@ func handleOutput(outType outputType, providerOutTyp reflect.Value) map[outputTy
 			rr[outType][defaultKey] = []value{providerOutTyp}
 		}
 	}
-	return rr
+	return rr, dropped
 }
 
-func detectCycle(graph map[reflect.Type]map[reflect.Type]bool) []reflect.Type {
-	visited := make(map[reflect.Type]bool)
-
-	var dfs func(reflect.Type, map[reflect.Type]bool, []reflect.Type) []reflect.Type
-	dfs = func(t reflect.Type, recursionStack map[reflect.Type]bool, path []reflect.Type) []reflect.Type {
-		if recursionStack[t] {
-			return slices.Clone(path)
-		}
-
-		if visited[t] {
-			return nil
-		}
-
-		visited[t] = true
-		recursionStack[t] = true
-		defer delete(recursionStack, t)
-
-		for dep := range graph[t] {
-			cycle := dfs(dep, recursionStack, append(slices.Clone(path), dep))
-			if len(cycle) > 0 {
-				return cycle
-			}
-		}
-		return nil
-	}
-
-	for t := range graph {
-		if visited[t] {
-			continue
-		}
+func getProvideAllInputs(log Logger, typ reflect.Type) []*providerInputType {
+	var input []*providerInputType
+	walkProvideInputs(log, typ, "", func(in *providerInputType, via string) {
+		input = append(input, in)
+	})
+	return input
+}
 
-		cycle := dfs(t, make(map[reflect.Type]bool), []reflect.Type{t})
-		if len(cycle) > 0 {
-			return cycle
-		}
-	}
-	return nil
+// walkProvideInputs visits the inputs of typ, a parameter object contributes its fields and the params of its
+// `InjectMethodPrefix` methods, via is the path of the field or method the input comes through.
+// The methods are dependency edges of the cycle analysis like the methods of an injection target.
+func walkProvideInputs(log Logger, typ reflect.Type, via string, fn func(in *providerInputType, via string)) {
+	walkInputs(log, typ, via, make(map[reflect.Type]bool), fn)
 }
 
-func getProvideAllInputs(typ reflect.Type) []*providerInputType {
-	var input []*providerInputType
+func walkInputs(log Logger, typ reflect.Type, via string, visited map[reflect.Type]bool, fn func(in *providerInputType, via string)) {
 	switch inTye := typ; inTye.Kind() {
 	case reflect.Interface, reflect.Ptr, reflect.Func:
-		input = append(input, &providerInputType{typ: inTye})
+		fn(&providerInputType{typ: inTye}, via)
 	case reflect.Struct:
+		if visited[inTye] {
+			return
+		}
+		visited[inTye] = true
+		defer delete(visited, inTye)
+
 		for j := 0; j < inTye.NumField(); j++ {
-			if !inTye.Field(j).IsExported() {
+			field := inTye.Field(j)
+			if !field.IsExported() || isValueField(field) {
 				continue
 			}
 
-			inTyp := inTye.Field(j).Type
-			if !isSupportedType(typ) {
+			if !isSupportedType(field.Type) {
 				continue
 			}
 
-			input = append(input, getProvideAllInputs(inTyp)...)
+			walkInputs(log, field.Type, joinVia(via, inTye, field.Name), visited, fn)
+		}
+
+		ptrTyp := reflect.PointerTo(inTye)
+		for _, i := range compileMethodPlan(ptrTyp).methods {
+			method := ptrTyp.Method(i)
+			for k := 1; k < method.Type.NumIn(); k++ {
+				walkInputs(log, method.Type.In(k), joinVia(via, inTye, method.Name), visited, fn)
+			}
 		}
 	case reflect.Map:
 		tt := &providerInputType{typ: inTye.Elem(), isMap: true, isList: inTye.Elem().Kind() == reflect.Slice}
 		if tt.isList {
 			tt.typ = tt.typ.Elem()
 		}
-		input = append(input, tt)
+		fn(tt, via)
 	case reflect.Slice:
-		input = append(input, &providerInputType{typ: inTye.Elem(), isList: true})
+		fn(&providerInputType{typ: inTye.Elem(), isList: true}, via)
 	default:
-		logger.Error().Msgf("incorrect input type, inTyp=%s kind=%s", inTye, inTye.Kind())
+		log.Error("incorrect input type", "type", inTye.String(), "kind", inTye.Kind().String())
 	}
-	return input
 }
 
-func buildDependencyGraph(providers map[outputType][]*providerFn) map[reflect.Type]map[reflect.Type]bool {
-	graph := make(map[reflect.Type]map[reflect.Type]bool)
-	// Pre-allocate map capacity to reduce rehash
-	for outTyp := range providers {
-		graph[outTyp] = make(map[reflect.Type]bool)
-	}
-
-	// Build dependency graph
-	for outTyp, nodes := range providers {
-		for _, providerNode := range nodes {
-			for _, input := range providerNode.inputList {
-				for _, provider := range getProvideAllInputs(input.typ) {
-					graph[outTyp][provider.typ] = true
-				}
-			}
-		}
+func joinVia(via string, typ reflect.Type, member string) string {
+	if via == "" {
+		return typ.String() + "." + member
 	}
-	return graph
+	return via + "." + member
 }
 
 // isSupportedType 检查是否为支持的类型
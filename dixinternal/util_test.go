@@ -0,0 +1,25 @@
+package dixinternal
+
+import (
+	"reflect"
+	"testing"
+)
+
+// recordLogger keeps the messages of each level
+type recordLogger struct {
+	NopLogger
+	errors []string
+}
+
+func (l *recordLogger) Error(msg string, kv ...any) { l.errors = append(l.errors, msg) }
+
+func TestWalkInputsLogger(t *testing.T) {
+	log := new(recordLogger)
+	walkProvideInputs(log, reflect.TypeOf(0), "", func(in *providerInputType, via string) {
+		t.Errorf("the int input should be skipped, input=%s", in.typ)
+	})
+
+	if len(log.errors) != 1 {
+		t.Fatalf("the incorrect input type should be logged to the container logger, errors=%v", log.errors)
+	}
+}
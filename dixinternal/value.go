@@ -0,0 +1,154 @@
+package dixinternal
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pubgo/funk/errors"
+	"github.com/pubgo/funk/v2/result"
+)
+
+const (
+	tagEnv     = "env"
+	tagFlag    = "flag"
+	tagDefault = "default"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// isValueField reports whether the field is resolved from env, flag or default tags instead of providers
+func isValueField(field reflect.StructField) bool {
+	for _, tag := range []string{tagEnv, tagFlag, tagDefault} {
+		if _, ok := field.Tag.Lookup(tag); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// injectValue resolves the field value, priority: parsed flag > env > flag default > default tag
+func (x *Dix) injectValue(field reflect.StructField, fv reflect.Value, opt Options) (r result.Error) {
+	var source, raw string
+	var found bool
+
+	flagName := field.Tag.Get(tagFlag)
+	flagVal, flagSet, flagDefined := lookupFlag(opt.FlagSet, flagName)
+	envName := field.Tag.Get(tagEnv)
+	envVal, envOk := lookupEnv(envName)
+	defVal, defOk := field.Tag.Lookup(tagDefault)
+
+	switch {
+	case flagSet:
+		source, raw, found = "flag "+flagName, flagVal, true
+	case envOk:
+		source, raw, found = "env "+envName, envVal, true
+	case flagDefined:
+		source, raw, found = "flag "+flagName, flagVal, true
+	case defOk:
+		source, raw, found = "default tag", defVal, true
+	}
+
+	if !found {
+		return
+	}
+
+	val, err := parseValue(field.Type, raw)
+	if err != nil {
+		return r.WrapErr(&errors.Err{
+			Msg:    fmt.Sprintf("failed to parse %s", source),
+			Detail: fmt.Sprintf("field=%s type=%s value=%q err=%v", field.Name, field.Type, raw, err),
+		})
+	}
+
+	fv.Set(val)
+	return
+}
+
+func lookupEnv(name string) (string, bool) {
+	if name == "" {
+		return "", false
+	}
+	return os.LookupEnv(name)
+}
+
+func lookupFlag(fs *flag.FlagSet, name string) (val string, set, defined bool) {
+	if fs == nil || name == "" {
+		return
+	}
+
+	f := fs.Lookup(name)
+	if f == nil {
+		return
+	}
+
+	fs.Visit(func(visited *flag.Flag) {
+		if visited.Name == name {
+			set = true
+		}
+	})
+	return f.Value.String(), set, true
+}
+
+// parseValue converts the string to string, bool, int, uint, float, time.Duration or a comma separated slice of them
+func parseValue(typ reflect.Type, raw string) (reflect.Value, error) {
+	val := reflect.New(typ).Elem()
+	if typ == durationType {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return val, err
+		}
+		val.SetInt(int64(d))
+		return val, nil
+	}
+
+	switch typ.Kind() {
+	case reflect.String:
+		val.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return val, err
+		}
+		val.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(raw, 0, typ.Bits())
+		if err != nil {
+			return val, err
+		}
+		val.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(raw, 0, typ.Bits())
+		if err != nil {
+			return val, err
+		}
+		val.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, typ.Bits())
+		if err != nil {
+			return val, err
+		}
+		val.SetFloat(f)
+	case reflect.Slice:
+		if strings.TrimSpace(raw) == "" {
+			return val, nil
+		}
+
+		parts := strings.Split(raw, ",")
+		val = reflect.MakeSlice(typ, 0, len(parts))
+		for _, part := range parts {
+			elem, err := parseValue(typ.Elem(), strings.TrimSpace(part))
+			if err != nil {
+				return val, err
+			}
+			val = reflect.Append(val, elem)
+		}
+	default:
+		return val, errors.Errorf("value type kind not support, kind=%s", typ.Kind())
+	}
+	return val, nil
+}
@@ -0,0 +1,147 @@
+package dixinternal
+
+import (
+	"flag"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseValue(t *testing.T) {
+	cases := []struct {
+		typ  reflect.Type
+		raw  string
+		want any
+	}{
+		{reflect.TypeOf(""), "a,b", "a,b"},
+		{reflect.TypeOf(true), "true", true},
+		{reflect.TypeOf(0), "0x10", 16},
+		{reflect.TypeOf(int8(0)), "-8", int8(-8)},
+		{reflect.TypeOf(uint16(0)), "80", uint16(80)},
+		{reflect.TypeOf(0.0), "1.5", 1.5},
+		{reflect.TypeOf(time.Duration(0)), "1m30s", 90 * time.Second},
+		{reflect.TypeOf([]string(nil)), "a, b ,c", []string{"a", "b", "c"}},
+		{reflect.TypeOf([]int(nil)), "1,2", []int{1, 2}},
+		{reflect.TypeOf([]time.Duration(nil)), "1s,2ms", []time.Duration{time.Second, 2 * time.Millisecond}},
+		{reflect.TypeOf([]string(nil)), " ", []string(nil)},
+	}
+
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("%s/%s", c.typ, c.raw), func(t *testing.T) {
+			val, err := parseValue(c.typ, c.raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(val.Interface(), c.want) {
+				t.Fatalf("got %#v, want %#v", val.Interface(), c.want)
+			}
+		})
+	}
+}
+
+func TestParseValueError(t *testing.T) {
+	cases := []struct {
+		typ reflect.Type
+		raw string
+	}{
+		{reflect.TypeOf(true), "yes"},
+		{reflect.TypeOf(int8(0)), "300"},
+		{reflect.TypeOf(uint(0)), "-1"},
+		{reflect.TypeOf(0.0), "x"},
+		{reflect.TypeOf(time.Duration(0)), "1"},
+		{reflect.TypeOf([]int(nil)), "1,x"},
+		{reflect.TypeOf(new(int)), "1"},
+		{reflect.TypeOf(map[string]string(nil)), "a"},
+	}
+
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("%s/%s", c.typ, c.raw), func(t *testing.T) {
+			if _, err := parseValue(c.typ, c.raw); err == nil {
+				t.Fatal("the value should not be parsed")
+			}
+		})
+	}
+}
+
+type valueTarget struct {
+	Addr string `flag:"addr" env:"DIX_TEST_ADDR" default:"tag"`
+}
+
+func TestInjectValuePriority(t *testing.T) {
+	cases := []struct {
+		name    string
+		env     string
+		flagDef bool
+		flagSet bool
+		want    string
+	}{
+		{"default tag", "", false, false, "tag"},
+		{"flag default", "", true, false, "flag-default"},
+		{"env", "env", true, false, "env"},
+		{"flag", "env", true, true, "flag"},
+	}
+
+	field, _ := reflect.TypeOf(valueTarget{}).FieldByName("Addr")
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if c.env != "" {
+				t.Setenv("DIX_TEST_ADDR", c.env)
+			}
+
+			var opt Options
+			if c.flagDef {
+				opt.FlagSet = flag.NewFlagSet("test", flag.ContinueOnError)
+				opt.FlagSet.String("addr", "flag-default", "")
+			}
+			if c.flagSet {
+				if err := opt.FlagSet.Parse([]string{"-addr=flag"}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var target valueTarget
+			if err := New(WithSilentLog()).injectValue(field, reflect.ValueOf(&target).Elem().Field(0), opt).GetErr(); err != nil {
+				t.Fatal(err)
+			}
+
+			if target.Addr != c.want {
+				t.Fatalf("addr=%q, want %q", target.Addr, c.want)
+			}
+		})
+	}
+}
+
+func TestInjectValueError(t *testing.T) {
+	t.Setenv("DIX_TEST_PORT", "http")
+
+	cases := []struct {
+		name   string
+		target any
+		want   string
+	}{
+		{"env", new(struct {
+			Port int `env:"DIX_TEST_PORT"`
+		}), "failed to parse env DIX_TEST_PORT"},
+		{"default", new(struct {
+			Timeout time.Duration `default:"10"`
+		}), "failed to parse default tag"},
+		{"pointer", new(struct {
+			Builder *strings.Builder `default:"builder"`
+		}), "failed to parse default tag"},
+		{"interface", new(struct {
+			Any fmt.Stringer `default:"x"`
+		}), "failed to parse default tag"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := New(WithSilentLog()).TryInject(c.target)
+			if err == nil || !strings.Contains(fmt.Sprint(err), c.want) {
+				t.Fatalf("err=%v, want %q", err, c.want)
+			}
+		})
+	}
+}
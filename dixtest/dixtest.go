@@ -0,0 +1,125 @@
+// Package dixtest helps to test the wiring of a dix container, the helpers report broken wiring
+// as test failures instead of panics
+//
+//	func TestWiring(t *testing.T) {
+//		di := dixtest.New(t, NewConfig, NewDB, NewServer)
+//		dixtest.RequireNoCycles(t, di)
+//		dixtest.Override(t, di, func() *Config { return &Config{DSN: "sqlite://:memory:"} })
+//		srv := dixtest.RequireResolvable[*Server](t, di)
+//		...
+//	}
+package dixtest
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pubgo/dix"
+)
+
+// New returns a container with the modules registered and closes it when the test ends,
+// a module is a dix.Module or another dix.Option applied to the container, or a provider func
+func New(t testing.TB, modules ...any) *dix.Dix {
+	t.Helper()
+
+	var opts []dix.Option
+	var providers []any
+	for _, m := range modules {
+		switch m := m.(type) {
+		case dix.Option:
+			opts = append(opts, m)
+		case func(opts *dix.Options):
+			opts = append(opts, m)
+		default:
+			providers = append(providers, m)
+		}
+	}
+
+	var di *dix.Dix
+	fatalOnPanic(t, func() { di = dix.New(opts...) }, "failed to create the container")
+	t.Cleanup(func() {
+		if err := di.Close(); err != nil {
+			t.Errorf("dixtest: failed to close the container: %v", err)
+		}
+	})
+
+	for _, p := range providers {
+		fatalOnPanic(t, func() { di.Provide(p) }, "invalid provider %T", p)
+	}
+	return di
+}
+
+// Fork returns a fork of the built baseline and closes it when the test ends,
+// tests using their own forks of one baseline can run in parallel, see dix.Dix.Fork
+func Fork(t testing.TB, baseline *dix.Dix) *dix.Dix {
+	t.Helper()
+
+	di := baseline.Fork()
+	t.Cleanup(func() {
+		if err := di.Close(); err != nil {
+			t.Errorf("dixtest: failed to close the fork: %v", err)
+		}
+	})
+	return di
+}
+
+// RequireResolvable resolves T from the container and fails the test when it can not be resolved,
+// T is anything a func parameter of Inject can be, e.g. *Server, []Handler or map[string]*DB
+func RequireResolvable[T any](t testing.TB, di *dix.Dix) T {
+	t.Helper()
+
+	var val T
+	if err := di.TryInject(func(v T) { val = v }); err != nil {
+		typ := reflect.TypeFor[T]()
+		msg := fmt.Sprintf("dixtest: %s is not resolvable: %v", typ, err)
+
+		switch typ.Kind() {
+		case reflect.Slice, reflect.Map:
+			typ = typ.Elem()
+		}
+		if typ.Kind() == reflect.Ptr || typ.Kind() == reflect.Interface || typ.Kind() == reflect.Func {
+			msg += "\n" + di.Explain(typ, "").String()
+		}
+		t.Fatal(msg)
+	}
+	return val
+}
+
+// RequireNoCycles fails the test when the providers have a dependency cycle
+func RequireNoCycles(t testing.TB, di *dix.Dix) {
+	t.Helper()
+
+	cycles := di.Cycles()
+	if len(cycles) == 0 {
+		return
+	}
+
+	var buf strings.Builder
+	for _, c := range cycles {
+		fmt.Fprintf(&buf, "\n  %s", c)
+	}
+	t.Fatalf("dixtest: found %d dependency cycles%s", len(cycles), buf.String())
+}
+
+// Override replaces the providers of the output types of fn with fn until the test ends, see dix.Dix.Override
+func Override(t testing.TB, di *dix.Dix, fn any) {
+	t.Helper()
+
+	var restore func()
+	fatalOnPanic(t, func() { restore = di.Override(fn) }, "invalid override %T", fn)
+	t.Cleanup(restore)
+}
+
+// fatalOnPanic turns the panic of an invalid option or provider into a test failure
+func fatalOnPanic(t testing.TB, fn func(), format string, args ...any) {
+	t.Helper()
+
+	defer func() {
+		if err := recover(); err != nil {
+			t.Fatalf("dixtest: "+format+": %v", append(args, err)...)
+		}
+	}()
+	fn()
+}
@@ -0,0 +1,76 @@
+package dixtest_test
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/pubgo/dix"
+	"github.com/pubgo/dix/dixtest"
+)
+
+type Config struct {
+	DSN string
+}
+
+// fatalTB records the fatal message and stops the goroutine like testing.T does
+type fatalTB struct {
+	testing.TB
+	fatal    string
+	cleanups []func()
+}
+
+func (t *fatalTB) Helper() {}
+
+func (t *fatalTB) Cleanup(fn func()) { t.cleanups = append(t.cleanups, fn) }
+
+func (t *fatalTB) Errorf(format string, args ...any) {}
+
+func (t *fatalTB) Fatalf(format string, args ...any) {
+	t.fatal = fmt.Sprintf(format, args...)
+	runtime.Goexit()
+}
+
+// runFatal runs fn with a fatalTB and returns the fatal message
+func runFatal(fn func(t testing.TB)) string {
+	tb := new(fatalTB)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn(tb)
+	}()
+	<-done
+
+	for _, c := range tb.cleanups {
+		c()
+	}
+	return tb.fatal
+}
+
+func TestNewFatal(t *testing.T) {
+	msg := runFatal(func(t testing.TB) { dixtest.New(t, dix.WithPlugins("dixtest", "missing")) })
+	if !strings.Contains(msg, "failed to create the container") {
+		t.Fatalf("the panic of dix.New should fail the test, msg=%q", msg)
+	}
+
+	msg = runFatal(func(t testing.TB) { dixtest.New(t, 42) })
+	if !strings.Contains(msg, "invalid provider int") {
+		t.Fatalf("the panic of Provide should fail the test, msg=%q", msg)
+	}
+}
+
+func TestOverrideRestore(t *testing.T) {
+	di := dixtest.New(t, func() *Config { return &Config{DSN: "mysql"} })
+
+	t.Run("override", func(t *testing.T) {
+		dixtest.Override(t, di, func() *Config { return &Config{DSN: "sqlite"} })
+		if cfg := dixtest.RequireResolvable[*Config](t, di); cfg.DSN != "sqlite" {
+			t.Fatalf("the override should provide the config, dsn=%s", cfg.DSN)
+		}
+	})
+
+	if cfg := dixtest.RequireResolvable[*Config](t, di); cfg.DSN != "mysql" {
+		t.Fatalf("the config should be restored when the subtest ends, dsn=%s", cfg.DSN)
+	}
+}
@@ -0,0 +1,111 @@
+package dixtest
+
+import (
+	"reflect"
+	"slices"
+	"sync"
+
+	"github.com/pubgo/dix"
+)
+
+// Call is a method call recorded by a fake
+type Call struct {
+	Method string
+	Args   []any
+}
+
+// Recorder records the method calls of a fake, it is safe for concurrent use
+type Recorder struct {
+	mu    sync.Mutex
+	calls []Call
+}
+
+// Record is called by the generated fake methods
+func (r *Recorder) Record(method string, args ...any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, Call{Method: method, Args: args})
+}
+
+// Calls returns the recorded calls in call order
+func (r *Recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.calls)
+}
+
+// CallsOf returns the recorded calls of the method in call order
+func (r *Recorder) CallsOf(method string) []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var calls []Call
+	for _, c := range r.calls {
+		if c.Method == method {
+			calls = append(calls, c)
+		}
+	}
+	return calls
+}
+
+// Called reports whether the method was called
+func (r *Recorder) Called(method string) bool {
+	return len(r.CallsOf(method)) > 0
+}
+
+// Reset drops the recorded calls
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = nil
+}
+
+// Fake is implemented by the fakes generated by cmds/dixfake
+type Fake interface {
+	DixFakeRecorder() *Recorder
+}
+
+var (
+	fakesMu sync.RWMutex
+	fakes   = make(map[reflect.Type]func(r *Recorder) reflect.Value)
+)
+
+// RegisterFake registers the constructor of the fake of interface I, the generated fakes register themselves in init,
+// a later registration of the same interface replaces the former one
+func RegisterFake[I any](newFake func(r *Recorder) I) {
+	typ := reflect.TypeFor[I]()
+	if typ.Kind() != reflect.Interface {
+		panic("dixtest: fake type must be an interface, type=" + typ.String())
+	}
+
+	fakesMu.Lock()
+	defer fakesMu.Unlock()
+	fakes[typ] = func(r *Recorder) reflect.Value { return reflect.ValueOf(newFake(r)) }
+}
+
+// WithFakes turns on the test mode of the container, an interface without any provider is resolved to a new fake
+// of the registered constructor, every method of the fake returns zero values and records the call, see FakeOf
+func WithFakes() dix.Option {
+	return dix.WithFakes(func(typ reflect.Type) (reflect.Value, bool) {
+		fakesMu.RLock()
+		newFake, ok := fakes[typ]
+		fakesMu.RUnlock()
+		if !ok {
+			return reflect.Value{}, false
+		}
+		return newFake(new(Recorder)), true
+	})
+}
+
+// FakeOf returns the recorder of the fake the container created for interface I, or nil when I was not faked
+func FakeOf[I any](di *dix.Dix) *Recorder {
+	fake, ok := di.Fake(reflect.TypeFor[I]())
+	if !ok {
+		return nil
+	}
+
+	if f, ok := fake.Interface().(Fake); ok {
+		return f.DixFakeRecorder()
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pubgo/dix"
+	"github.com/pubgo/funk/assert"
+	"github.com/pubgo/funk/recovery"
+)
+
+type Redis struct {
+	Addr string
+}
+
+type Handler struct {
+	Redis *Redis
+	All   map[string]*Redis
+}
+
+// go run -race ./concurrent
+func main() {
+	defer recovery.Exit()
+
+	var count atomic.Int32
+	di := dix.New()
+	di.Provide(func() *Redis {
+		count.Add(1)
+		time.Sleep(10 * time.Millisecond)
+		return &Redis{Addr: "localhost:6379"}
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			h := dix.Inject(di, new(Handler))
+			assert.If(h.Redis == nil || h.All["default"] == nil, "inject error")
+		}()
+
+		go func() {
+			defer wg.Done()
+			di.Provide(func() map[string]*Redis { return nil })
+			_ = di.Graph()
+		}()
+	}
+	wg.Wait()
+
+	assert.If(count.Load() != 1, "provider should be evaluated once, count=%d", count.Load())
+	fmt.Println("provider count:", count.Load())
+}
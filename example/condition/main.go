@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/pubgo/dix"
+	"github.com/pubgo/funk/assert"
+	"github.com/pubgo/funk/recovery"
+)
+
+type Config struct {
+	Cache string
+}
+
+type Cache interface {
+	Name() string
+}
+
+type memoryCache struct{}
+
+func (memoryCache) Name() string { return "memory" }
+
+type redisCache struct{}
+
+func (redisCache) Name() string { return "redis" }
+
+type Mailer interface {
+	Send(to string) string
+}
+
+type mockMailer struct{}
+
+func (mockMailer) Send(to string) string { return "mock mail to " + to }
+
+type smtpMailer struct{}
+
+func (smtpMailer) Send(to string) string { return "smtp mail to " + to }
+
+func build(profile string, cache string) *dix.Dix {
+	di := dix.New(dix.WithProfiles(profile))
+	di.Provide(func() *Config { return &Config{Cache: cache} })
+
+	// the provider is enabled by the injected config
+	di.Provide(func() Cache { return memoryCache{} }, dix.If(func(cfg *Config) bool { return cfg.Cache == "memory" }))
+	di.Provide(func() Cache { return redisCache{} }, dix.If(func(cfg *Config) bool { return cfg.Cache == "redis" }))
+
+	// the provider is enabled by the active profile
+	di.Provide(func() Mailer { return mockMailer{} }, dix.Profile("dev", "test"))
+	di.Provide(func() Mailer { return smtpMailer{} }, dix.Profile("prod"))
+	return di
+}
+
+func main() {
+	defer recovery.Exit()
+
+	dev := build("dev", "memory")
+	dix.Inject(dev, func(c Cache, m Mailer) {
+		fmt.Println("dev:", c.Name(), m.Send("alice"))
+		assert.If(c.Name() != "memory", "the memory cache is enabled by the config")
+		assert.If(m.Send("alice") != "mock mail to alice", "the mock mailer is enabled by the dev profile")
+	})
+
+	prod := build("prod", "redis")
+	dix.Inject(prod, func(c Cache, m Mailer) {
+		fmt.Println("prod:", c.Name(), m.Send("bob"))
+		assert.If(c.Name() != "redis", "the redis cache is enabled by the config")
+		assert.If(m.Send("bob") != "smtp mail to bob", "the smtp mailer is enabled by the prod profile")
+	})
+
+	fmt.Println(dix.Explain[Mailer](prod, ""))
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pubgo/dix"
+	"github.com/pubgo/dix/dixconfig"
+	"github.com/pubgo/funk/assert"
+	"github.com/pubgo/funk/errors"
+	"github.com/pubgo/funk/recovery"
+)
+
+const configYaml = `
+db:
+  default:
+    host: localhost
+    port: 5432
+  replica:
+    host: replica
+server:
+  addr: ":8080"
+`
+
+type DBConfig struct {
+	Host    string        `yaml:"host"`
+	Port    int           `yaml:"port" default:"3306"`
+	Timeout time.Duration `yaml:"timeout" default:"1s"`
+}
+
+func (c *DBConfig) Validate() error {
+	if c.Host == "" {
+		return errors.New("db host is required")
+	}
+	return nil
+}
+
+type ServerConfig struct {
+	Addr  string   `yaml:"addr"`
+	Hosts []string `yaml:"hosts"`
+}
+
+func main() {
+	defer recovery.Exit()
+
+	file := filepath.Join(os.TempDir(), "dix-config-example.yaml")
+	assert.Must(os.WriteFile(file, []byte(configYaml), 0o644))
+	defer os.Remove(file)
+
+	assert.Must(os.Setenv("APP_SERVER_HOSTS", "a.com,b.com"))
+
+	di := dix.New()
+	dixconfig.Provide(di, dixconfig.WithFiles(file), dixconfig.WithEnvPrefix("APP"))
+	dixconfig.BindMap[*DBConfig](di, "db")
+	dixconfig.Bind[*ServerConfig](di, "server")
+
+	di.Inject(func(db *DBConfig, dbs map[string]*DBConfig, srv *ServerConfig) {
+		fmt.Println(db, dbs["replica"], srv)
+		assert.If(db.Port != 5432, "port not match")
+		assert.If(dbs["replica"].Port != 3306, "default port not match")
+		assert.If(dbs["replica"].Timeout != time.Second, "default timeout not match")
+		assert.If(len(srv.Hosts) != 2, "env hosts not match")
+	})
+}
@@ -2,9 +2,13 @@ package main
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/pubgo/dix"
 	"github.com/pubgo/dix/dixglobal"
+	"github.com/pubgo/funk/assert"
 	"github.com/pubgo/funk/recovery"
+	"github.com/pubgo/funk/try"
 )
 
 func main() {
@@ -19,6 +23,8 @@ func main() {
 		B struct{}
 
 		C struct{}
+
+		D struct{}
 	)
 
 	dixglobal.Provide(func(*B) *A {
@@ -29,6 +35,40 @@ func main() {
 		return new(B)
 	})
 
+	di := dix.New(dix.WithRejectCycle())
+	di.Provide(func(*B) *A {
+		return new(A)
+	})
+	di.Provide(func(*C) *B {
+		return new(B)
+	})
+	err := try.Try(func() error {
+		di.Provide(func(*A) *C {
+			return new(C)
+		})
+		return nil
+	})
+	assert.If(err == nil || !strings.Contains(err.Error(), "circular dependency"), "cycle should be rejected")
+	fmt.Println(err)
+
+	di = dix.New()
+	di.Provide(func(*B) *A {
+		return new(A)
+	})
+	di.Provide(func(p struct{ C *C }) *B {
+		return new(B)
+	})
+	di.Provide(func(*A) *C {
+		return new(C)
+	})
+	di.Provide(func(*D) *D {
+		return new(D)
+	})
+	for _, cycle := range di.Cycles() {
+		fmt.Println(cycle.String())
+	}
+	assert.If(len(di.Cycles()) != 2, "all cycles should be reported")
+
 	dixglobal.Provide(func(*A) *C {
 		return new(C)
 	})
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/pubgo/dix"
+	"github.com/pubgo/dix/dixhttp"
+)
+
+type (
+	Config struct{ Addr string }
+	DB     struct{ cfg *Config }
+	Cache  struct{}
+	Server struct {
+		db    *DB
+		cache *Cache
+	}
+)
+
+func main() {
+	di := dix.New()
+	di.Provide(func() *Config { return &Config{Addr: "127.0.0.1:8080"} })
+	di.Provide(func(cfg *Config) *DB { return &DB{cfg: cfg} })
+	di.Provide(func() *Cache { return new(Cache) })
+	di.Provide(func(db *DB, cache *Cache) *Server { return &Server{db: db, cache: cache} })
+
+	cfg := dix.Inject(di, new(struct {
+		Config *Config
+		Server *Server
+	})).Config
+
+	mux := http.NewServeMux()
+	mux.Handle("/debug/dix/", http.StripPrefix("/debug/dix", dixhttp.Handler(di)))
+
+	log.Printf("open http://%s/debug/dix/", cfg.Addr)
+	log.Fatal(http.ListenAndServe(cfg.Addr, mux))
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/pubgo/dix"
+	"github.com/pubgo/funk/assert"
+	"github.com/pubgo/funk/recovery"
+)
+
+type Cache struct{ Name string }
+
+func main() {
+	defer recovery.Exit()
+
+	di := dix.New(dix.WithValuesNull())
+	di.Provide(func() *Cache { return &Cache{Name: "memory"} })
+	di.Provide(func() map[string]*Cache {
+		return map[string]*Cache{"redis": {Name: "redis"}, "backup": nil}
+	})
+	di.Provide(func() *Cache { return &Cache{Name: "lru"} })
+
+	dix.Inject(di, func(c *Cache, all map[string]*Cache) {
+		fmt.Println("injected:", c.Name)
+	})
+
+	e := dix.Explain[*Cache](di, "")
+	fmt.Print(e)
+	assert.If(len(e.Candidates) != 3, "all providers are candidates")
+	assert.If(e.Winner == nil || e.Winner.Seq != e.Candidates[2].Seq, "the last provider wins")
+	assert.If(len(e.Candidates[1].Dropped) != 1, "the nil backup cache is dropped")
+
+	fmt.Print(dix.Explain[*Cache](di, "redis"))
+}
@@ -0,0 +1,49 @@
+package main
+
+//go:generate dixfake -type Mailer,Audit -o dixfake.go .
+
+import (
+	"fmt"
+
+	"github.com/pubgo/dix"
+	"github.com/pubgo/dix/dixtest"
+	"github.com/pubgo/funk/assert"
+	"github.com/pubgo/funk/recovery"
+)
+
+type (
+	Mailer interface {
+		Send(to, body string) error
+	}
+
+	Audit interface {
+		Log(event string)
+	}
+
+	Handler struct {
+		mailer Mailer
+		audit  Audit
+	}
+)
+
+func (h *Handler) Signup(email string) error {
+	h.audit.Log("signup")
+	return h.mailer.Send(email, "welcome")
+}
+
+func main() {
+	defer recovery.Exit()
+
+	// in a test: di := dixtest.New(t, dixtest.WithFakes(), NewHandler)
+	di := dix.New(dixtest.WithFakes())
+	di.Provide(func(m Mailer, a Audit) *Handler { return &Handler{mailer: m, audit: a} })
+
+	dix.Inject(di, func(h *Handler) {
+		assert.Must(h.Signup("a@example.com"))
+	})
+
+	calls := dixtest.FakeOf[Mailer](di).CallsOf("Send")
+	fmt.Println("mailer calls:", calls)
+	assert.If(len(calls) != 1 || calls[0].Args[0] != "a@example.com", "the handler sends the welcome mail")
+	assert.If(!dixtest.FakeOf[Audit](di).Called("Log"), "the handler logs the signup")
+}
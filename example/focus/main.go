@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pubgo/dix"
+	"github.com/pubgo/funk/assert"
+	"github.com/pubgo/funk/recovery"
+)
+
+type (
+	Config  struct{}
+	DB      struct{}
+	Cache   struct{}
+	Tracer  interface{ Trace(string) }
+	Handler struct{}
+	Server  struct{}
+	Metrics struct{}
+)
+
+func NewConfig() *Config                                       { return new(Config) }
+func NewDB(*Config) *DB                                        { return new(DB) }
+func NewCache(*Config) map[string]*Cache                       { return map[string]*Cache{"default": {}, "session": nil} }
+func NewHandler(*DB, *Cache, struct{ Tracer Tracer }) *Handler { return new(Handler) }
+func NewServer(*Handler) *Server                               { return new(Server) }
+func NewMetrics(*Config) *Metrics                              { return new(Metrics) }
+
+func main() {
+	defer recovery.Exit()
+
+	di := dix.New(dix.WithValuesNull())
+	di.Provide(NewConfig)
+	di.Provide(NewDB)
+	di.Provide(NewCache)
+	di.Provide(NewHandler)
+	di.Provide(NewServer)
+	di.Provide(NewMetrics)
+
+	dix.Inject(di, func(*Cache) {})
+
+	// the wiring of the handler: what it is built from, one provider deep, grouped by package
+	g := di.Graph(dix.Format(dix.FormatMermaid), dix.Ancestors("*main.Handler", 1), dix.ClusterByPackage(), dix.Highlight())
+	fmt.Println(g.Providers)
+	assert.If(strings.Contains(g.Providers, "NewMetrics"), "metrics is not a dependency of the handler")
+	assert.If(strings.Contains(g.Providers, "NewConfig"), "config is two providers away")
+	assert.If(!strings.Contains(g.Providers, "stroke:#C0392B"), "the missing tracer is highlighted")
+	assert.If(!strings.Contains(g.Providers, "stroke:#E67E22"), "the nil session cache is highlighted")
+
+	// everything built from the config
+	g = di.Graph(dix.Descendants("*main.Config", 0))
+	fmt.Println(g.ProviderTypes)
+	assert.If(!strings.Contains(g.ProviderTypes, `"*main.Handler" -> "*main.Server"`), "server depends on config through the handler")
+}
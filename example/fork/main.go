@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pubgo/dix"
+	"github.com/pubgo/funk/assert"
+	"github.com/pubgo/funk/recovery"
+)
+
+type (
+	Config struct{ Env string }
+	DB     struct{ id int }
+	Mailer interface{ Send(to string) string }
+)
+
+type smtpMailer struct{}
+
+func (smtpMailer) Send(to string) string { return "smtp:" + to }
+
+type fakeMailer struct{}
+
+func (fakeMailer) Send(to string) string { return "fake:" + to }
+
+func main() {
+	defer recovery.Exit()
+
+	var dbs int
+	base := dix.New()
+	base.Provide(func() *Config { return &Config{Env: "prod"} })
+	base.Provide(func(*Config) *DB { dbs++; return &DB{id: dbs} })
+	base.Provide(func() Mailer { return smtpMailer{} })
+	assert.Must(base.Build(context.Background()))
+
+	// a fork shares the built db and isolates the override of the mailer
+	fork := base.Fork()
+	fork.Override(func() Mailer { return fakeMailer{} })
+	dix.Inject(fork, func(db *DB, m Mailer) {
+		fmt.Println("fork:", db.id, m.Send("a@example.com"))
+		assert.If(db.id != 1, "the fork shares the db of the baseline")
+		assert.If(m.Send("") != "fake:", "the fork uses the fake mailer")
+	})
+
+	dix.Inject(base, func(m Mailer) {
+		assert.If(m.Send("") != "smtp:", "the baseline keeps the smtp mailer")
+	})
+
+	// a clone evaluates every provider again
+	clone := base.Clone()
+	dix.Inject(clone, func(db *DB, self *dix.Dix) {
+		fmt.Println("clone:", db.id)
+		assert.If(db.id != 2, "the clone builds its own db")
+		assert.If(self != clone, "the clone provides itself")
+	})
+}
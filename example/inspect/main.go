@@ -0,0 +1,50 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/pubgo/dix"
+	"github.com/pubgo/funk/assert"
+	"github.com/pubgo/funk/recovery"
+)
+
+type (
+	Config struct{ DSN string }
+	DB     struct{ cfg *Config }
+	Server struct{ db *DB }
+)
+
+func main() {
+	defer recovery.Exit()
+
+	di := dix.New()
+	di.Provide(func() *Config { return &Config{DSN: "sqlite://:memory:"} })
+	di.Provide(func(cfg *Config) *DB { return &DB{cfg: cfg} })
+	di.Provide(func(p struct{ DB *DB }) map[string]*Server {
+		return map[string]*Server{"public": {db: p.DB}, "admin": {db: p.DB}}
+	})
+
+	dix.Inject(di, func(servers map[string]*Server) {
+		fmt.Println("servers:", len(servers))
+	})
+
+	g := di.Inspect()
+	for _, p := range g.NodesOf(dix.NodeProvider) {
+		fmt.Printf("%s initialized=%v cost=%s inputs=%v outputs=%v\n",
+			p.Provider.Func, p.Provider.Initialized, p.Provider.Cost, p.Provider.Inputs, p.Provider.Outputs)
+	}
+
+	for _, e := range g.EdgesTo(dix.TypeNodeID("*main.DB")) {
+		fmt.Println("*main.DB is provided by", g.Node(e.From).Label)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	assert.Must(enc.Encode(g))
+
+	fmt.Println(di.Graph().Providers)
+	fmt.Println(di.Graph(dix.Format(dix.FormatMermaid)).ProviderTypes)
+	fmt.Println(di.Graph(dix.Format(dix.FormatPlantUML)).Objects)
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+
+	"github.com/pubgo/dix"
+	"github.com/pubgo/funk/recovery"
+)
+
+type (
+	Config struct{}
+	Cache  interface{ Get(key string) string }
+)
+
+func main() {
+	defer recovery.Exit()
+
+	// debug logs of the provider evaluation go to the slog handler
+	log := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	di := dix.New(dix.WithValuesNull(), dix.WithLogger(dix.SlogLogger(log)))
+	di.Provide(func() *Config { return new(Config) })
+	dix.Inject(di, func(cfg *Config, caches []Cache) {})
+
+	// the "provider not found" warning of []Cache is dropped
+	quiet := dix.New(dix.WithValuesNull(), dix.WithLogger(dix.SlogLogger(log)), dix.WithLogLevel(slog.LevelError))
+	dix.Inject(quiet, func(caches []Cache) {})
+
+	// nothing is logged
+	silent := dix.New(dix.WithValuesNull(), dix.WithSilentLog())
+	dix.Inject(silent, func(caches []Cache) {})
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pubgo/dix"
+	"github.com/pubgo/funk/assert"
+	"github.com/pubgo/funk/recovery"
+)
+
+type (
+	Config struct{ DSN string }
+	Pool   struct{ dsn string }
+	DB     struct{ pool *Pool }
+	Server struct{ db *DB }
+)
+
+var pools int
+
+var ConfigModule = dix.Module("config",
+	dix.Provide(func() *Config { return &Config{DSN: "sqlite://:memory:"} }),
+)
+
+var StorageModule = dix.Module("storage",
+	dix.Include(ConfigModule),
+	dix.Private(func(cfg *Config) *Pool { pools++; return &Pool{dsn: cfg.DSN} }),
+	dix.Provide(func(p *Pool) *DB { return &DB{pool: p} }),
+	dix.Invoke(func(p *Pool) { fmt.Println("storage pool:", p.dsn) }),
+)
+
+var APIModule = dix.Module("api",
+	dix.Include(StorageModule, ConfigModule),
+	dix.Provide(func(db *DB) *Server { return &Server{db: db} }),
+)
+
+func main() {
+	defer recovery.Exit()
+
+	// storage is included by api and installed again here, it is registered once
+	di := dix.New(APIModule, StorageModule)
+
+	dix.Inject(di, func(srv *Server) {
+		assert.If(srv.db.pool == nil, "the public db is built from the private pool")
+	})
+	assert.If(pools != 1, "the storage module is installed once")
+
+	// the pool is private to the storage module
+	err := di.TryInject(func(*Pool) {})
+	fmt.Println("inject private pool:", err != nil)
+	assert.If(err == nil, "the private pool is hidden outside of the storage module")
+
+	g := di.Graph(dix.Format(dix.FormatMermaid), dix.ClusterByModule())
+	fmt.Println(g.Providers)
+	assert.If(!strings.Contains(g.Providers, `"module storage"`), "the providers are grouped by module")
+}
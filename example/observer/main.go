@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"sync/atomic"
+	"time"
+
+	"github.com/pubgo/dix"
+	"github.com/pubgo/funk/assert"
+	"github.com/pubgo/funk/recovery"
+)
+
+type (
+	Config struct{}
+	DB     struct{}
+)
+
+// metrics counts the provider calls and prints the injection spans
+type metrics struct {
+	dix.NopObserver
+	providers atomic.Int64
+	resolved  atomic.Int64
+}
+
+func (m *metrics) ProviderFinished(p dix.ProviderEvent, err error, dur time.Duration) {
+	m.providers.Add(1)
+	fmt.Printf("provider %s -> %s cost=%s err=%v\n", p.Func, p.Output, dur, err)
+}
+
+func (m *metrics) InjectStarted(target reflect.Type) {
+	fmt.Println("inject start", target)
+}
+
+func (m *metrics) InjectFinished(target reflect.Type, err error, dur time.Duration) {
+	fmt.Println("inject end", target, dur, err)
+}
+
+func (m *metrics) ValueResolved(typ reflect.Type, ns string) {
+	m.resolved.Add(1)
+}
+
+func main() {
+	defer recovery.Exit()
+
+	m := new(metrics)
+	di := dix.New(dix.WithObserver(m))
+	di.Provide(func() *Config { return new(Config) })
+	di.Provide(func(*Config) *DB { return new(DB) })
+
+	dix.Inject(di, func(db *DB, cfg *Config) {})
+	assert.If(m.providers.Load() != 2, "both providers should be observed")
+	assert.If(m.resolved.Load() != 3, "every resolved value should be observed")
+}
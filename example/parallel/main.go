@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pubgo/dix"
+	"github.com/pubgo/funk/assert"
+	"github.com/pubgo/funk/recovery"
+)
+
+type (
+	DB    struct{}
+	Redis struct{}
+	Kafka struct{}
+)
+
+type Server struct {
+	db    *DB
+	redis *Redis
+	kafka *Kafka
+}
+
+func dial[T any](name string) func() *T {
+	return func() *T {
+		fmt.Println("dial", name)
+		time.Sleep(100 * time.Millisecond)
+		return new(T)
+	}
+}
+
+func main() {
+	defer recovery.Exit()
+
+	di := dix.New(dix.WithParallelInit(4))
+	di.Provide(dial[DB]("db"))
+	di.Provide(dial[Redis]("redis"))
+	di.Provide(dial[Kafka]("kafka"))
+	di.Provide(func(db *DB, redis *Redis, kafka *Kafka) *Server {
+		return &Server{db: db, redis: redis, kafka: kafka}
+	})
+
+	now := time.Now()
+	assert.Must(di.Build(context.Background()))
+	cost := time.Since(now)
+	fmt.Println("build cost:", cost)
+
+	di.Inject(func(srv *Server) {
+		assert.If(srv.db == nil || srv.redis == nil || srv.kafka == nil, "inject error")
+	})
+
+	for _, stat := range di.Stats() {
+		fmt.Println(stat.Func, stat.Duration, "goroutine", stat.Goroutine)
+	}
+
+	report := di.BootReport()
+	fmt.Println(report)
+	assert.If(report.Total < 300*time.Millisecond, "total should sum the provider durations")
+	assert.If(report.CriticalPathDuration >= report.Total, "critical path should be shorter than the total")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	di = dix.New(dix.WithParallelInit(4))
+	di.Provide(dial[DB]("db"))
+	assert.If(di.Build(ctx) == nil, "build should be canceled")
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/pubgo/dix"
+	"github.com/pubgo/funk/assert"
+	"github.com/pubgo/funk/recovery"
+)
+
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error) { return json.Marshal(v) }
+
+type xmlCodec struct{}
+
+func (xmlCodec) Marshal(v any) ([]byte, error) { return xml.Marshal(v) }
+
+type yamlCodec struct{}
+
+func (yamlCodec) Marshal(v any) ([]byte, error) { return nil, fmt.Errorf("not implemented") }
+
+// the codec packages register themselves, the application decides which ones are enabled
+func init() {
+	dix.Register("codec", "json", func() Codec { return jsonCodec{} })
+	dix.Register("codec", "xml", func() Codec { return xmlCodec{} })
+	dix.Register("codec", "yaml", func() Codec { return yamlCodec{} })
+}
+
+type Config struct {
+	Codecs []string
+}
+
+func newDix(opts ...dix.Option) (gErr error) {
+	defer recovery.Err(&gErr)
+	dix.New(opts...)
+	return
+}
+
+func main() {
+	defer recovery.Exit()
+
+	var names []string
+	for _, p := range dix.Plugins("codec") {
+		names = append(names, p.Name)
+	}
+	fmt.Println("registered codecs:", names)
+
+	cfg := Config{Codecs: []string{"json", "xml"}}
+	di := dix.New(dix.WithPlugins("codec", cfg.Codecs...))
+
+	dix.Inject(di, func(codecs map[string]Codec) {
+		keys := slices.Sorted(maps.Keys(codecs))
+		fmt.Println("enabled codecs:", keys)
+		assert.If(!slices.Equal(keys, cfg.Codecs), "the enabled plugins are the namespaces of the map")
+
+		data := assert.Must1(codecs["json"].Marshal(map[string]int{"a": 1}))
+		fmt.Println("json:", string(data))
+	})
+
+	// a plugin which is not registered fails at New
+	err := newDix(dix.WithPlugins("codec", "toml"))
+	fmt.Println("unknown plugin:", err != nil)
+	assert.If(err == nil, "the unknown plugin is rejected")
+
+	g := di.Graph(dix.Format(dix.FormatMermaid), dix.ClusterByModule())
+	fmt.Println(g.Providers)
+	assert.If(!strings.Contains(g.Providers, "[codec/json]"), "the graph shows the registry entries")
+	assert.If(!strings.Contains(g.Providers, `"registry codec"`), "the plugins are grouped by registry")
+}
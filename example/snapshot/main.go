@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pubgo/dix"
+	"github.com/pubgo/funk/assert"
+	"github.com/pubgo/funk/recovery"
+)
+
+type (
+	Config struct{}
+	DB     struct{}
+	Cache  struct{}
+)
+
+func NewConfig() *Config           { return new(Config) }
+func NewDB(*Config) *DB            { return new(DB) }
+func NewCache() map[string]*Cache  { return map[string]*Cache{"default": {}, "session": {}} }
+func NewCaches() map[string]*Cache { return map[string]*Cache{"default": {}} }
+
+func build(providers ...any) *dix.Dix {
+	di := dix.New()
+	for _, p := range providers {
+		di.Provide(p)
+	}
+	assert.Must(di.Build(context.Background()))
+	return di
+}
+
+func main() {
+	defer recovery.Exit()
+
+	// the committed snapshot
+	path := filepath.Join(os.TempDir(), "dix.snapshot.json")
+	data := assert.Must1(build(NewConfig, NewDB, NewCache).Snapshot().MarshalIndent())
+	assert.Must(os.WriteFile(path, data, 0o644))
+	fmt.Println("snapshot written to", path)
+
+	committed := assert.Must1(dix.LoadSnapshot(assert.Must1(os.ReadFile(path))))
+
+	// the same wiring has no diff
+	assert.If(!dix.DiffGraphs(committed, build(NewConfig, NewDB, NewCache).Snapshot()).Empty(), "same wiring should have no diff")
+
+	// the session cache is gone
+	diff := dix.DiffGraphs(committed, build(NewConfig, NewDB, NewCaches).Snapshot())
+	fmt.Print(diff)
+	assert.If(len(diff.AddedProviders) != 1 || len(diff.RemovedProviders) != 1, "cache provider should be replaced")
+	assert.If(len(diff.ChangedNamespaces) != 1, "session namespace should be removed")
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/pubgo/dix"
+	"github.com/pubgo/funk/assert"
+	"github.com/pubgo/funk/recovery"
+)
+
+type (
+	Config struct{}
+	DB     struct{}
+	Legacy struct{}
+)
+
+func main() {
+	defer recovery.Exit()
+
+	di := dix.New(dix.WithUnusedCheck(dix.UnusedFail))
+	di.Provide(func() *Config { return new(Config) })
+	di.Provide(func() *Config { return new(Config) })
+	di.Provide(func(*Config) *DB { return new(DB) })
+	di.Provide(func() *Legacy { return new(Legacy) })
+
+	dix.Inject(di, func(db *DB) {})
+
+	report := di.Unused()
+	fmt.Print(report)
+
+	// nobody depends on *Legacy, the first *Config is shadowed by the second one
+	assert.If(len(report.Providers) != 1 || report.Providers[0].Outputs[0] != "*main.Legacy", "legacy provider should be unused")
+	assert.If(len(report.Objects) != 1 || report.Objects[0].Type != "*main.Config", "shadowed config should be unused")
+	assert.If(di.CheckUnused() == nil, "check should fail on the unused provider")
+}
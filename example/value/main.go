@@ -0,0 +1,64 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pubgo/dix"
+	"github.com/pubgo/funk/assert"
+	"github.com/pubgo/funk/recovery"
+	"github.com/pubgo/funk/try"
+)
+
+type Redis struct {
+	Addr string
+}
+
+type Server struct {
+	Redis   *Redis
+	DBURL   string        `env:"DB_URL"`
+	Port    int           `flag:"port" default:"8080"`
+	Debug   bool          `env:"APP_DEBUG" default:"false"`
+	Timeout time.Duration `default:"3s"`
+	Hosts   []string      `env:"APP_HOSTS"`
+}
+
+func main() {
+	defer recovery.Exit()
+
+	assert.Must(os.Setenv("DB_URL", "postgres://localhost/app"))
+	assert.Must(os.Setenv("APP_HOSTS", "a.com, b.com"))
+
+	fs := flag.NewFlagSet("example", flag.ContinueOnError)
+	fs.Int("port", 9090, "server port")
+	assert.Must(fs.Parse([]string{"-port=9999"}))
+
+	di := dix.New(dix.WithFlagSet(fs))
+	di.Provide(func() *Redis { return &Redis{Addr: "localhost:6379"} })
+
+	srv := dix.Inject(di, new(Server))
+	fmt.Printf("%+v\n", srv)
+	assert.If(srv.DBURL != "postgres://localhost/app", "env not match")
+	assert.If(srv.Port != 9999, "flag not match")
+	assert.If(srv.Timeout != 3*time.Second, "default not match")
+	assert.If(len(srv.Hosts) != 2 || srv.Hosts[1] != "b.com", "slice not match")
+
+	di.Inject(func(p struct {
+		Redis *Redis
+		Port  int `flag:"port"`
+	},
+	) {
+		assert.If(p.Port != 9999, "flag not match")
+	})
+
+	assert.Must(os.Setenv("APP_DEBUG", "yes"))
+	err := try.Try(func() error {
+		di.Inject(new(Server))
+		return nil
+	})
+	assert.If(err == nil || !strings.Contains(err.Error(), "APP_DEBUG"), "env error not match")
+	fmt.Println(err)
+}
@@ -3,9 +3,11 @@ module github.com/pubgo/dix
 go 1.23.0
 
 require (
+	github.com/BurntSushi/toml v1.3.2
 	github.com/kr/pretty v0.3.1
 	github.com/pubgo/funk v0.5.68
 	github.com/samber/lo v1.51.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (